@@ -0,0 +1,67 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/core/glib"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// sizeWatcher watches the size allocated to a gtk.Overlay. It does this by
+// adding an invisible overlay child that always covers the whole overlay, since
+// gotk4 has no way to hook into a widget's size allocation otherwise.
+type sizeWatcher struct {
+	*gtk.DrawingArea
+	width  int
+	height int
+	idle   glib.SourceHandle
+	// always is true if onResize is called after every allocation, even if
+	// the size didn't change.
+	always bool
+}
+
+// watchSize calls onResize after the given overlay is allocated a new size.
+// onResize is called from an idle callback, because the widget tree must not
+// be changed while it is being allocated.
+func watchSize(overlay *gtk.Overlay, onResize func(width, height int)) *sizeWatcher {
+	return newSizeWatcher(overlay, false, onResize)
+}
+
+// watchAllocation is like watchSize, except onResize is called after every
+// allocation of the overlay, even if its size didn't change. This is needed if
+// onResize depends on the size of the overlay's children.
+func watchAllocation(overlay *gtk.Overlay, onResize func(width, height int)) *sizeWatcher {
+	return newSizeWatcher(overlay, true, onResize)
+}
+
+func newSizeWatcher(overlay *gtk.Overlay, always bool, onResize func(width, height int)) *sizeWatcher {
+	w := &sizeWatcher{DrawingArea: gtk.NewDrawingArea(), always: always}
+	w.AddCSSClass("adaptive-sizewatcher")
+	w.SetCanTarget(false)
+	w.SetCanFocus(false)
+	w.SetHExpand(true)
+	w.SetVExpand(true)
+	w.ConnectResize(func(width, height int) {
+		if !w.always && w.width == width && w.height == height {
+			return
+		}
+
+		w.width = width
+		w.height = height
+
+		if w.idle != 0 {
+			return
+		}
+
+		w.idle = glib.IdleAddPriority(glib.PriorityHighIdle, func() {
+			w.idle = 0
+			onResize(w.width, w.height)
+		})
+	})
+
+	overlay.AddOverlay(w)
+	return w
+}
+
+// Size returns the last allocated size.
+func (w *sizeWatcher) Size() (width, height int) {
+	return w.width, w.height
+}
@@ -0,0 +1,246 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/core/glib"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// BreakpointCondition is a condition on the size of a widget. It returns true
+// if the given size matches.
+type BreakpointCondition func(width, height int) bool
+
+// BreakpointMinWidth matches if the width is at least the given width.
+func BreakpointMinWidth(width int) BreakpointCondition {
+	return func(w, h int) bool { return w >= width }
+}
+
+// BreakpointMaxWidth matches if the width is at most the given width.
+func BreakpointMaxWidth(width int) BreakpointCondition {
+	return func(w, h int) bool { return w <= width }
+}
+
+// BreakpointMinHeight matches if the height is at least the given height.
+func BreakpointMinHeight(height int) BreakpointCondition {
+	return func(w, h int) bool { return h >= height }
+}
+
+// BreakpointMaxHeight matches if the height is at most the given height.
+func BreakpointMaxHeight(height int) BreakpointCondition {
+	return func(w, h int) bool { return h <= height }
+}
+
+// BreakpointMinAspectRatio matches if width/height is at least the given
+// ratio.
+func BreakpointMinAspectRatio(ratio float64) BreakpointCondition {
+	return func(w, h int) bool { return h > 0 && float64(w)/float64(h) >= ratio }
+}
+
+// BreakpointMaxAspectRatio matches if width/height is at most the given ratio.
+func BreakpointMaxAspectRatio(ratio float64) BreakpointCondition {
+	return func(w, h int) bool { return h > 0 && float64(w)/float64(h) <= ratio }
+}
+
+// Matches returns true if the given size matches the condition.
+func (c BreakpointCondition) Matches(width, height int) bool {
+	return c(width, height)
+}
+
+// And returns a condition that matches only if both c and other match.
+func (c BreakpointCondition) And(other BreakpointCondition) BreakpointCondition {
+	return func(w, h int) bool { return c(w, h) && other(w, h) }
+}
+
+// Or returns a condition that matches if either c or other matches.
+func (c BreakpointCondition) Or(other BreakpointCondition) BreakpointCondition {
+	return func(w, h int) bool { return c(w, h) || other(w, h) }
+}
+
+// Breakpoint describes a set of changes that are applied to the UI when a
+// condition on the size of a BreakpointBin matches. The changes are reverted
+// once the condition no longer matches.
+type Breakpoint struct {
+	cond    BreakpointCondition
+	setters []breakpointSetter
+	applied bool
+}
+
+type breakpointSetter struct {
+	apply  func()
+	revert func()
+}
+
+// NewBreakpoint creates a new breakpoint with the given condition.
+func NewBreakpoint(cond BreakpointCondition) *Breakpoint {
+	return &Breakpoint{cond: cond}
+}
+
+// Condition returns the breakpoint's condition.
+func (b *Breakpoint) Condition() BreakpointCondition {
+	return b.cond
+}
+
+// IsApplied returns true if the breakpoint is currently applied.
+func (b *Breakpoint) IsApplied() bool {
+	return b.applied
+}
+
+// AddCallbacks adds a pair of callbacks that are called when the breakpoint is
+// applied and reverted. Either callback may be nil.
+func (b *Breakpoint) AddCallbacks(apply, revert func()) {
+	if apply == nil {
+		apply = func() {}
+	}
+	if revert == nil {
+		revert = func() {}
+	}
+
+	b.addSetter(breakpointSetter{apply, revert})
+}
+
+// AddProperty sets the given object property when the breakpoint is applied.
+// The property's previous value is restored when the breakpoint is reverted.
+func (b *Breakpoint) AddProperty(obj glib.Objector, property string, value interface{}) {
+	object := glib.InternObject(obj)
+	var old interface{}
+
+	b.addSetter(breakpointSetter{
+		apply: func() {
+			old = object.ObjectProperty(property)
+			object.SetObjectProperty(property, value)
+		},
+		revert: func() {
+			object.SetObjectProperty(property, old)
+			old = nil
+		},
+	})
+}
+
+// AddCSSClass adds the given CSS class to the widget when the breakpoint is
+// applied and removes it when the breakpoint is reverted. A class that the
+// widget already had is left alone.
+func (b *Breakpoint) AddCSSClass(widget gtk.Widgetter, class string) {
+	w := gtk.BaseWidget(widget)
+	var added bool
+
+	b.addSetter(breakpointSetter{
+		apply: func() {
+			added = !w.HasCSSClass(class)
+			w.AddCSSClass(class)
+		},
+		revert: func() {
+			if added {
+				w.RemoveCSSClass(class)
+				added = false
+			}
+		},
+	})
+}
+
+func (b *Breakpoint) addSetter(setter breakpointSetter) {
+	b.setters = append(b.setters, setter)
+	if b.applied {
+		setter.apply()
+	}
+}
+
+func (b *Breakpoint) apply() {
+	if b.applied {
+		return
+	}
+	b.applied = true
+	for _, setter := range b.setters {
+		setter.apply()
+	}
+}
+
+func (b *Breakpoint) revert() {
+	if !b.applied {
+		return
+	}
+	b.applied = false
+	// Revert in the reverse order so that overlapping setters restore the
+	// right values.
+	for i := len(b.setters) - 1; i >= 0; i-- {
+		b.setters[i].revert()
+	}
+}
+
+// BreakpointBin is a widget that holds a single child and applies breakpoints
+// according to its own allocated size. At most one breakpoint is applied at a
+// time: the last added breakpoint whose condition matches.
+type BreakpointBin struct {
+	*gtk.Overlay
+	size *sizeWatcher
+
+	breakpoints []*Breakpoint
+	current     *Breakpoint
+}
+
+// NewBreakpointBin creates a new BreakpointBin.
+func NewBreakpointBin() *BreakpointBin {
+	b := &BreakpointBin{}
+	b.Overlay = gtk.NewOverlay()
+	b.Overlay.AddCSSClass("adaptive-breakpointbin")
+	b.size = watchSize(b.Overlay, func(int, int) { b.update() })
+	return b
+}
+
+// AddBreakpoint adds a breakpoint into the bin.
+func (b *BreakpointBin) AddBreakpoint(bp *Breakpoint) {
+	b.breakpoints = append(b.breakpoints, bp)
+	b.update()
+}
+
+// RemoveBreakpoint removes the given breakpoint from the bin. The breakpoint is
+// reverted if it was applied.
+func (b *BreakpointBin) RemoveBreakpoint(bp *Breakpoint) {
+	for i, breakpoint := range b.breakpoints {
+		if breakpoint == bp {
+			b.breakpoints = append(b.breakpoints[:i], b.breakpoints[i+1:]...)
+			break
+		}
+	}
+
+	if b.current == bp {
+		b.current = nil
+		bp.revert()
+	}
+
+	b.update()
+}
+
+// CurrentBreakpoint returns the currently applied breakpoint, or nil if none
+// is.
+func (b *BreakpointBin) CurrentBreakpoint() *Breakpoint {
+	return b.current
+}
+
+func (b *BreakpointBin) update() {
+	w, h := b.size.Size()
+	if w == 0 && h == 0 {
+		// Not allocated yet.
+		return
+	}
+
+	var match *Breakpoint
+	for i := len(b.breakpoints) - 1; i >= 0; i-- {
+		if b.breakpoints[i].cond.Matches(w, h) {
+			match = b.breakpoints[i]
+			break
+		}
+	}
+
+	if match == b.current {
+		return
+	}
+
+	if b.current != nil {
+		b.current.revert()
+	}
+
+	b.current = match
+
+	if b.current != nil {
+		b.current.apply()
+	}
+}
@@ -0,0 +1,63 @@
+package adaptive_test
+
+import (
+	"testing"
+
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleBreakpointBin() {
+	testapp.Run("breakpoint-bin", func(app *gtk.Application) {
+		adaptive.Init()
+
+		label := gtk.NewLabel("This window is wide.")
+		label.SetWrap(true)
+
+		narrow := adaptive.NewBreakpoint(adaptive.BreakpointMaxWidth(400))
+		narrow.AddProperty(label, "label", "This window is narrow.")
+		narrow.AddCSSClass(label, "dim-label")
+
+		bin := adaptive.NewBreakpointBin()
+		bin.SetChild(label)
+		bin.AddBreakpoint(narrow)
+
+		w := testapp.NewWindow(app, "Breakpoint", 500, 200)
+		w.SetChild(bin)
+		w.Show()
+	})
+	// Output:
+}
+
+func TestBreakpointCondition(t *testing.T) {
+	tests := []struct {
+		name string
+		cond adaptive.BreakpointCondition
+		w, h int
+		want bool
+	}{
+		{"min width below", adaptive.BreakpointMinWidth(400), 399, 0, false},
+		{"min width at", adaptive.BreakpointMinWidth(400), 400, 0, true},
+		{"max width at", adaptive.BreakpointMaxWidth(400), 400, 0, true},
+		{"max width above", adaptive.BreakpointMaxWidth(400), 401, 0, false},
+		{"min height at", adaptive.BreakpointMinHeight(300), 0, 300, true},
+		{"max height above", adaptive.BreakpointMaxHeight(300), 0, 301, false},
+		{"min aspect ratio at", adaptive.BreakpointMinAspectRatio(2), 400, 200, true},
+		{"min aspect ratio below", adaptive.BreakpointMinAspectRatio(2), 399, 200, false},
+		{"max aspect ratio at", adaptive.BreakpointMaxAspectRatio(1), 200, 200, true},
+		{"max aspect ratio above", adaptive.BreakpointMaxAspectRatio(1), 201, 200, false},
+		{"min aspect ratio without height", adaptive.BreakpointMinAspectRatio(0), 400, 0, false},
+		{"max aspect ratio without height", adaptive.BreakpointMaxAspectRatio(10), 400, 0, false},
+		{"and both", adaptive.BreakpointMinWidth(400).And(adaptive.BreakpointMinHeight(300)), 400, 300, true},
+		{"and one", adaptive.BreakpointMinWidth(400).And(adaptive.BreakpointMinHeight(300)), 400, 299, false},
+		{"or one", adaptive.BreakpointMaxWidth(400).Or(adaptive.BreakpointMaxHeight(300)), 500, 300, true},
+		{"or neither", adaptive.BreakpointMaxWidth(400).Or(adaptive.BreakpointMaxHeight(300)), 500, 301, false},
+	}
+
+	for _, test := range tests {
+		if got := test.cond.Matches(test.w, test.h); got != test.want {
+			t.Errorf("%s: Matches(%d, %d) = %v, want %v", test.name, test.w, test.h, got, test.want)
+		}
+	}
+}
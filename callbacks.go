@@ -0,0 +1,36 @@
+package adaptive
+
+// callbacks is a list of callbacks that can be removed.
+type callbacks struct {
+	fns []callback
+	id  int
+}
+
+type callback struct {
+	id int
+	fn func()
+}
+
+func (c *callbacks) add(fn func()) func() {
+	c.id++
+	id := c.id
+
+	c.fns = append(c.fns, callback{id, fn})
+
+	return func() {
+		for i, cb := range c.fns {
+			if cb.id == id {
+				c.fns = append(c.fns[:i], c.fns[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (c *callbacks) call() {
+	// Copy the callbacks in case one of them unsubscribes.
+	fns := append([]callback(nil), c.fns...)
+	for _, cb := range fns {
+		cb.fn()
+	}
+}
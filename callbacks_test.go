@@ -0,0 +1,43 @@
+package adaptive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCallbacks(t *testing.T) {
+	var c callbacks
+	var calls []string
+	var removeB, removeC func()
+
+	c.add(func() { calls = append(calls, "a") })
+	removeB = c.add(func() {
+		calls = append(calls, "b")
+		// Removing callbacks while they are being called must neither skip
+		// nor repeat any of the others in this call.
+		removeB()
+		removeC()
+	})
+	removeC = c.add(func() { calls = append(calls, "c") })
+	c.add(func() { calls = append(calls, "d") })
+
+	c.call()
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("first call: got %v, want %v", calls, want)
+	}
+
+	calls = nil
+	c.call()
+	if want := []string{"a", "d"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("second call: got %v, want %v", calls, want)
+	}
+
+	// Removing a callback twice is harmless.
+	removeB()
+
+	calls = nil
+	c.call()
+	if want := []string{"a", "d"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("third call: got %v, want %v", calls, want)
+	}
+}
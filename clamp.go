@@ -0,0 +1,300 @@
+package adaptive
+
+import (
+	"math"
+
+	"github.com/diamondburned/gotk4/pkg/core/glib"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+const (
+	defaultClampMaximumSize         = 600
+	defaultClampTighteningThreshold = 400
+)
+
+// clamper calculates the margins that clamp a child to a maximum width.
+type clamper struct {
+	child   *gtk.Widget // the widget whose margins are set
+	classed *gtk.Widget // the widget that gets the size CSS classes
+	class   string
+	max     int
+	thres   int
+}
+
+func newClamper(classed *gtk.Widget) clamper {
+	return clamper{
+		classed: classed,
+		max:     defaultClampMaximumSize,
+		thres:   defaultClampTighteningThreshold,
+	}
+}
+
+// childSize returns the width that the child gets out of the given width. The
+// child takes the whole width up to the tightening threshold, then grows ever
+// slower until it reaches the maximum size.
+func (c *clamper) childSize(width int) int {
+	lower := c.thres
+	if lower > c.max {
+		lower = c.max
+	}
+	amplitude := c.max - lower
+	upper := 4*amplitude + lower
+
+	switch {
+	case width <= lower:
+		return width
+	case width >= upper:
+		return c.max
+	default:
+		progress := float64(width-lower) / float64(upper-lower)
+		return lower + int(math.Round(easeOutCubic(progress)*float64(amplitude)))
+	}
+}
+
+func (c *clamper) update(width int) {
+	if c.child == nil || width == 0 {
+		return
+	}
+
+	size := c.childSize(width)
+	margin := (width - size) / 2
+	c.child.SetMarginStart(margin)
+	c.child.SetMarginEnd(margin)
+
+	var class string
+	switch {
+	case size <= c.thres:
+		class = "adaptive-clamp-small"
+	case size >= c.max:
+		class = "adaptive-clamp-large"
+	default:
+		class = "adaptive-clamp-medium"
+	}
+
+	if class != c.class {
+		if c.class != "" {
+			c.classed.RemoveCSSClass(c.class)
+		}
+		c.class = class
+		c.classed.AddCSSClass(class)
+	}
+}
+
+// Clamp is a component that acts similar to libadwaita's AdwClamp. It limits
+// the width of its child to a maximum size and centers it. Below the
+// tightening threshold, the child takes the whole width, and between the
+// threshold and the maximum size, the margins around the child tighten
+// smoothly.
+//
+// The clamp has the CSS class "adaptive-clamp-small" while the child is no
+// wider than the tightening threshold, "adaptive-clamp-large" once the child
+// reaches the maximum size, and "adaptive-clamp-medium" in between.
+//
+// A Clamp can be put inside a gtk.ScrolledWindow. For scrollable children like
+// gtk.ListView, put a ClampScrollable around the gtk.ScrolledWindow instead.
+type Clamp struct {
+	*gtk.Widget
+	overlay *gtk.Overlay
+	scroll  *gtk.ScrolledWindow
+	bin     *Bin
+	size    *sizeWatcher
+	clamper clamper
+}
+
+// NewClamp creates a new clamp.
+func NewClamp() *Clamp {
+	c := &Clamp{}
+
+	c.bin = NewBin()
+	c.bin.SetHExpand(true)
+
+	// The bin is kept in a scrolled window without scrollbars, so that its
+	// margins never make the clamp wider than it needs to be.
+	c.scroll = gtk.NewScrolledWindow()
+	c.scroll.SetPolicy(gtk.PolicyExternal, gtk.PolicyNever)
+	c.scroll.SetPropagateNaturalWidth(true)
+	c.scroll.SetPropagateNaturalHeight(true)
+	c.scroll.SetChild(c.bin)
+
+	c.overlay = gtk.NewOverlay()
+	c.overlay.AddCSSClass("adaptive-clamp")
+	c.overlay.SetChild(c.scroll)
+
+	c.Widget = gtk.BaseWidget(c.overlay)
+	c.clamper = newClamper(c.Widget)
+	c.clamper.child = gtk.BaseWidget(c.bin)
+	c.size = watchSize(c.overlay, func(int, int) { c.update() })
+
+	return c
+}
+
+// SetChild sets the clamp's child.
+func (c *Clamp) SetChild(child gtk.Widgetter) {
+	c.bin.SetChild(child)
+	c.update()
+}
+
+// Child returns the clamp's child.
+func (c *Clamp) Child() gtk.Widgetter {
+	return c.bin.Child()
+}
+
+// SetMaximumSize sets the maximum width of the child. It is 600 by default.
+func (c *Clamp) SetMaximumSize(size int) {
+	c.clamper.max = size
+	c.update()
+}
+
+// MaximumSize returns the maximum width of the child.
+func (c *Clamp) MaximumSize() int {
+	return c.clamper.max
+}
+
+// SetTighteningThreshold sets the width below which the child takes the whole
+// width of the clamp. It is 400 by default.
+func (c *Clamp) SetTighteningThreshold(threshold int) {
+	c.clamper.thres = threshold
+	c.update()
+}
+
+// TighteningThreshold returns the tightening threshold.
+func (c *Clamp) TighteningThreshold() int {
+	return c.clamper.thres
+}
+
+func (c *Clamp) update() {
+	width, _ := c.size.Size()
+	c.clamper.update(width)
+
+	// Never let the clamp get smaller than the child.
+	if child := c.bin.Child(); child != nil {
+		min, _, _, _ := gtk.BaseWidget(child).Measure(gtk.OrientationHorizontal, -1)
+		c.scroll.SetMinContentWidth(min)
+	}
+}
+
+// ClampScrollable is a Clamp for scrollable children like gtk.ListView. Unlike
+// libadwaita's AdwClampScrollable, it is not scrollable itself, since gotk4
+// can't implement gtk.Scrollable, so it must not be put inside a
+// gtk.ScrolledWindow, which would wrap it in a gtk.Viewport and lay out the
+// whole list at once. Instead, it goes around the gtk.ScrolledWindow and clamps
+// the scrolled window's child, which keeps scrolling the whole width of the
+// clamp while its content is clamped.
+//
+// The clamp holds a scrolled window of its own by default. An existing one can
+// be clamped with SetScrolledWindow instead, and its child can still be set on
+// the scrolled window directly.
+type ClampScrollable struct {
+	*gtk.Widget
+	overlay *gtk.Overlay
+	scroll  *gtk.ScrolledWindow
+	childID glib.SignalHandle
+	size    *sizeWatcher
+	clamper clamper
+}
+
+// NewClampScrollable creates a new scrollable clamp.
+func NewClampScrollable() *ClampScrollable {
+	c := &ClampScrollable{}
+
+	c.overlay = gtk.NewOverlay()
+	c.overlay.AddCSSClass("adaptive-clamp")
+	c.overlay.AddCSSClass("adaptive-clamp-scrollable")
+
+	c.Widget = gtk.BaseWidget(c.overlay)
+	c.clamper = newClamper(c.Widget)
+	c.size = watchSize(c.overlay, func(int, int) { c.update() })
+
+	scroll := gtk.NewScrolledWindow()
+	scroll.SetPolicy(gtk.PolicyExternal, gtk.PolicyAutomatic)
+	scroll.SetHExpand(true)
+	scroll.SetVExpand(true)
+	c.SetScrolledWindow(scroll)
+
+	return c
+}
+
+// SetScrolledWindow sets the scrolled window whose child is clamped. The
+// scrolled window must not have a parent, since the clamp holds it. The clamp's
+// previous scrolled window is removed along with its child.
+func (c *ClampScrollable) SetScrolledWindow(scroll *gtk.ScrolledWindow) {
+	if c.scroll == scroll {
+		return
+	}
+
+	if c.scroll != nil {
+		c.scroll.HandlerDisconnect(c.childID)
+		c.setClamped(nil)
+	}
+
+	c.scroll = scroll
+	c.overlay.SetChild(scroll)
+	c.childID = scroll.NotifyProperty("child", func() {
+		c.setClamped(c.scroll.Child())
+	})
+	c.setClamped(scroll.Child())
+}
+
+// ScrolledWindow returns the scrolled window that holds the child.
+func (c *ClampScrollable) ScrolledWindow() *gtk.ScrolledWindow {
+	return c.scroll
+}
+
+// SetChild sets the clamp's child. It is the same as setting the child of the
+// clamp's scrolled window. The child should be scrollable, such as a
+// gtk.ListView, and it must not be in a gtk.ScrolledWindow already.
+func (c *ClampScrollable) SetChild(child gtk.Widgetter) {
+	c.scroll.SetChild(child)
+}
+
+// Child returns the clamp's child.
+func (c *ClampScrollable) Child() gtk.Widgetter {
+	return c.scroll.Child()
+}
+
+// setClamped sets the widget whose margins are clamped and resets the margins
+// of the previous one.
+func (c *ClampScrollable) setClamped(child gtk.Widgetter) {
+	if c.clamper.child != nil {
+		if child != nil && glib.ObjectEq(c.clamper.child, child) {
+			return
+		}
+		c.clamper.child.SetMarginStart(0)
+		c.clamper.child.SetMarginEnd(0)
+		c.clamper.child = nil
+	}
+
+	if child != nil {
+		c.clamper.child = gtk.BaseWidget(child)
+	}
+
+	c.update()
+}
+
+// SetMaximumSize sets the maximum width of the child. It is 600 by default.
+func (c *ClampScrollable) SetMaximumSize(size int) {
+	c.clamper.max = size
+	c.update()
+}
+
+// MaximumSize returns the maximum width of the child.
+func (c *ClampScrollable) MaximumSize() int {
+	return c.clamper.max
+}
+
+// SetTighteningThreshold sets the width below which the child takes the whole
+// width of the clamp. It is 400 by default.
+func (c *ClampScrollable) SetTighteningThreshold(threshold int) {
+	c.clamper.thres = threshold
+	c.update()
+}
+
+// TighteningThreshold returns the tightening threshold.
+func (c *ClampScrollable) TighteningThreshold() int {
+	return c.clamper.thres
+}
+
+func (c *ClampScrollable) update() {
+	width, _ := c.size.Size()
+	c.clamper.update(width)
+}
@@ -0,0 +1,35 @@
+package adaptive
+
+import "testing"
+
+func TestClamperChildSize(t *testing.T) {
+	tests := []struct {
+		max, thres int
+		width      int
+		want       int
+	}{
+		// Up to the threshold, the child takes the whole width.
+		{600, 400, 0, 0},
+		{600, 400, 300, 300},
+		{600, 400, 400, 400},
+		// Past it, the child grows ever slower until it reaches the maximum
+		// size at four times the difference past the threshold.
+		{600, 400, 500, 466},
+		{600, 400, 800, 575},
+		{600, 400, 1199, 600},
+		{600, 400, 1200, 600},
+		{600, 400, 5000, 600},
+		// A threshold above the maximum size clamps right at the maximum.
+		{600, 800, 500, 500},
+		{600, 800, 600, 600},
+		{600, 800, 700, 600},
+	}
+
+	for _, test := range tests {
+		c := clamper{max: test.max, thres: test.thres}
+		if got := c.childSize(test.width); got != test.want {
+			t.Errorf("max %d, threshold %d: childSize(%d) = %d, want %d",
+				test.max, test.thres, test.width, got, test.want)
+		}
+	}
+}
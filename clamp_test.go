@@ -0,0 +1,54 @@
+package adaptive_test
+
+import (
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleClamp() {
+	testapp.Run("clamp", func(app *gtk.Application) {
+		adaptive.Init()
+
+		label := gtk.NewLabel("This text never gets wider than 400 pixels, no matter how wide the window is.")
+		label.SetWrap(true)
+
+		clamp := adaptive.NewClamp()
+		clamp.SetMaximumSize(400)
+		clamp.SetTighteningThreshold(300)
+		clamp.SetChild(label)
+
+		scroll := gtk.NewScrolledWindow()
+		scroll.SetPolicy(gtk.PolicyNever, gtk.PolicyAutomatic)
+		scroll.SetChild(clamp)
+
+		w := testapp.NewWindow(app, "Example Clamp", 800, 300)
+		w.SetChild(scroll)
+		w.Show()
+	})
+	// Output:
+}
+
+func ExampleClampScrollable() {
+	testapp.Run("clamp-scrollable", func(app *gtk.Application) {
+		adaptive.Init()
+
+		text := gtk.NewTextView()
+		text.SetWrapMode(gtk.WrapWordChar)
+		text.Buffer().SetText("The text is clamped, but the whole window scrolls it.")
+
+		scroll := gtk.NewScrolledWindow()
+		scroll.SetPolicy(gtk.PolicyNever, gtk.PolicyAutomatic)
+		scroll.SetChild(text)
+
+		// The clamp goes around the scrolled window and clamps its child.
+		clamp := adaptive.NewClampScrollable()
+		clamp.SetMaximumSize(400)
+		clamp.SetScrolledWindow(scroll)
+
+		w := testapp.NewWindow(app, "Example Scrollable Clamp", 800, 300)
+		w.SetChild(clamp)
+		w.Show()
+	})
+	// Output:
+}
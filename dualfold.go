@@ -0,0 +1,163 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// DualFoldRevealStartAction and DualFoldRevealEndAction are the names of the
+// stateful boolean actions that reveal or hide the start and the end sidebar of
+// a DualFold. The actions are in the group returned by DualFold.ActionGroup,
+// which is inserted into the DualFold with the "dualfold" prefix, so their full
+// names are "dualfold.reveal-start" and "dualfold.reveal-end".
+const (
+	DualFoldRevealStartAction = "reveal-start"
+	DualFoldRevealEndAction   = "reveal-end"
+)
+
+// DualFold is a component with a sidebar at both the start and the end of the
+// content, such as a channel list and a member list. Each sidebar is a Fold of
+// its own that folds at its own threshold and is revealed independently, except
+// that only one of them can be revealed at a time while both are folded. While
+// the end sidebar is revealed modally, the start sidebar is dimmed as well, and
+// clicking anywhere outside the end sidebar hides it.
+//
+// The thresholds of both sidebars are compared against the size of the whole
+// DualFold, not against the space that is left next to the other sidebar.
+type DualFold struct {
+	*gtk.Widget
+	start *Fold
+	end   *Fold
+	// sidebar holds the start sidebar's child with the dimming on top of it.
+	sidebar *gtk.Overlay
+	// dimming dims the start sidebar while the end sidebar is revealed.
+	dimming *gtk.Box
+	actions *gio.SimpleActionGroup
+}
+
+// NewDualFold creates a new DualFold. The sidebars are mirrored for
+// right-to-left text directions, just like NewFoldPacked.
+func NewDualFold() *DualFold {
+	d := &DualFold{
+		start: NewFoldPacked(gtk.PackStart),
+		end:   NewFoldPacked(gtk.PackEnd),
+	}
+
+	// The end fold lives inside the start fold's content.
+	d.start.SetChild(d.end)
+	d.start.AddCSSClass("adaptive-dualfold")
+	d.end.fsizeRef = gtk.BaseWidget(d.start.overlay)
+
+	// Since the end fold is checked against the start fold's size, it has to
+	// be checked again whenever the start fold changes as well.
+	watchSize(d.start.overlay, func(int, int) { d.end.updateLayout() })
+	d.start.NotifyFolded(func(bool) { d.end.updateLayout() })
+
+	d.start.NotifyRevealed(func(bool) { d.closeOther(d.start, d.end) })
+	d.end.NotifyRevealed(func(bool) { d.closeOther(d.end, d.start) })
+
+	// The start fold's dimming only covers its content, which the end
+	// sidebar is a part of, so the start sidebar gets a dimming of its own,
+	// which goes on top of the sidebar's child.
+	d.dimming = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	d.dimming.AddCSSClass("adaptive-dualfold-dimming")
+	d.dimming.SetCanTarget(false)
+	d.dimming.SetCanFocus(false)
+	d.dimming.SetVisible(false)
+
+	d.sidebar = gtk.NewOverlay()
+	d.sidebar.AddOverlay(d.dimming)
+	d.start.SetSideChild(d.sidebar)
+
+	d.end.NotifyFolded(func(bool) { d.updateDimming() })
+	d.end.NotifyRevealed(func(bool) { d.updateDimming() })
+
+	// Controller for clicking outside the modal end sidebar, such as on the
+	// start sidebar. The end fold's own controller only sees clicks on its
+	// content, so this one is bound to the start fold and captures the
+	// clicks before the start sidebar gets them.
+	clicker := gtk.NewGestureClick()
+	clicker.SetPropagationPhase(gtk.PhaseCapture)
+	clicker.ConnectPressed(func(n int, x, y float64) {
+		if !d.endIsModal() || d.isInEnd(x, y) {
+			return
+		}
+		d.end.SetRevealSide(false)
+		clicker.SetState(gtk.EventSequenceClaimed)
+	})
+	d.start.overlay.AddController(clicker)
+
+	d.actions = gio.NewSimpleActionGroup()
+	d.actions.AddAction(d.start.newRevealAction(DualFoldRevealStartAction))
+	d.actions.AddAction(d.end.newRevealAction(DualFoldRevealEndAction))
+	d.start.overlay.InsertActionGroup("dualfold", d.actions)
+
+	d.Widget = d.start.Widget
+	return d
+}
+
+// ActionGroup returns the DualFold's action group, which contains the
+// DualFoldRevealStartAction and the DualFoldRevealEndAction. It is already
+// inserted into the DualFold as "dualfold", but it can also be inserted into
+// the window so that the actions work from anywhere in it.
+func (d *DualFold) ActionGroup() *gio.SimpleActionGroup {
+	return d.actions
+}
+
+// Start returns the fold of the start sidebar. Use it to set the sidebar's
+// threshold and width, or to connect a FoldRevealButton to it. The sidebar's
+// child must be set with SetStartChild instead, since the DualFold dims it.
+func (d *DualFold) Start() *Fold {
+	return d.start
+}
+
+// End returns the fold of the end sidebar. Use it to set the sidebar's child,
+// threshold and width, or to connect a FoldRevealButton to it.
+func (d *DualFold) End() *Fold {
+	return d.end
+}
+
+// SetStartChild sets the start sidebar's content.
+func (d *DualFold) SetStartChild(child gtk.Widgetter) {
+	d.sidebar.SetChild(child)
+}
+
+// SetEndChild sets the end sidebar's content.
+func (d *DualFold) SetEndChild(child gtk.Widgetter) {
+	d.end.SetSideChild(child)
+}
+
+// SetChild sets the main content between the two sidebars.
+func (d *DualFold) SetChild(child gtk.Widgetter) {
+	d.end.SetChild(child)
+}
+
+// endIsModal returns true if the end sidebar is folded and revealed over the
+// content modally.
+func (d *DualFold) endIsModal() bool {
+	return d.end.IsFolded() && d.end.IsModal() && d.end.SideIsRevealed()
+}
+
+func (d *DualFold) updateDimming() {
+	d.dimming.SetVisible(d.endIsModal())
+}
+
+// isInEnd returns true if the given point within the start fold is on the end
+// fold, which includes its sidebar.
+func (d *DualFold) isInEnd(x, y float64) bool {
+	target := d.start.overlay.Pick(x, y, gtk.PickDefault)
+	if target == nil {
+		return false
+	}
+	widget := gtk.BaseWidget(target)
+	return glib.ObjectEq(widget, d.end.overlay) || widget.IsAncestor(d.end.overlay)
+}
+
+// closeOther hides the other sidebar if both sidebars are folded and the
+// given fold was just revealed.
+func (d *DualFold) closeOther(fold, other *Fold) {
+	if fold.IsFolded() && fold.SideIsRevealed() && other.IsFolded() && other.SideIsRevealed() {
+		other.SetRevealSide(false)
+	}
+}
@@ -0,0 +1,46 @@
+package adaptive_test
+
+import (
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleDualFold() {
+	testapp.Run("dual-fold", func(app *gtk.Application) {
+		adaptive.Init()
+
+		stack := newStack()
+		stack.SetHExpand(true)
+
+		stackside := gtk.NewStackSidebar()
+		stackside.SetStack(stack)
+
+		members := gtk.NewLabel("Members")
+		members.SetVAlign(gtk.AlignStart)
+
+		fold := adaptive.NewDualFold()
+		fold.SetStartChild(stackside)
+		fold.SetEndChild(members)
+		fold.SetChild(stack)
+		fold.Start().SetFoldThreshold(500)
+		fold.End().SetFoldThreshold(700)
+
+		startButton := adaptive.NewFoldRevealButton()
+		startButton.ConnectFold(fold.Start())
+
+		endButton := adaptive.NewFoldRevealButton()
+		endButton.SetIconName("system-users-symbolic")
+		endButton.ConnectFold(fold.End())
+
+		h := gtk.NewHeaderBar()
+		h.PackStart(startButton)
+		h.PackEnd(endButton)
+
+		w := testapp.NewWindow(app, "Example Dual Sidebar", 800, 300)
+		w.SetChild(fold)
+		w.SetTitlebar(h)
+		w.Show()
+	})
+	// Output:
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
 	"github.com/diamondburned/gotk4/pkg/glib/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
 )
@@ -12,6 +13,12 @@ import (
 // FoldRevealButtonIcon is the default icon name for a fold reveal button.
 const FoldRevealButtonIcon = "open-menu-symbolic"
 
+// FoldRevealAction is the name of the stateful boolean action that reveals or
+// hides the folded sidebar. The action is in the group returned by
+// Fold.ActionGroup, which is inserted into the fold with the "fold" prefix, so
+// its full name is "fold.reveal-sidebar".
+const FoldRevealAction = "reveal-sidebar"
+
 // FoldRevealButton is a button that toggles whether or not the fold's sidebar
 // should be revealed.
 type FoldRevealButton struct {
@@ -44,60 +51,157 @@ func (b *FoldRevealButton) SetIconName(icon string) {
 }
 
 // ConnectFold connects the current sidebar reveal button to the given
-// sidebar.
-func (b *FoldRevealButton) ConnectFold(fold *Fold) {
-	b.Button.ConnectClicked(func() {
+// sidebar. The returned callback disconnects the button from the sidebar.
+func (b *FoldRevealButton) ConnectFold(fold *Fold) func() {
+	clicked := b.Button.ConnectClicked(func() {
 		fold.SetRevealSide(b.Button.Active())
 	})
 
-	fold.NotifyRevealed(func(revealed bool) {
+	unrevealed := fold.NotifyRevealed(func(revealed bool) {
 		b.Button.SetActive(revealed)
 	})
 
-	fold.NotifyFolded(func(folded bool) {
+	unfolded := fold.NotifyFolded(func(folded bool) {
 		b.SetRevealChild(folded)
 		b.Button.SetActive(fold.SideIsRevealed())
 		b.Button.SetSensitive(folded)
 	})
+
+	return func() {
+		b.Button.HandlerDisconnect(clicked)
+		unrevealed()
+		unfolded()
+	}
+}
+
+// FoldGroup is a group of folds that have synchronized fold and reveal states.
+// The first fold in the group is used as the basis for the width. Folds with a
+// FoldAlways or FoldNever policy keep folding according to their own policy.
+type FoldGroup struct {
+	folds   []*Fold
+	saved   map[*Fold]func() bool
+	unbinds []func()
+	mutex   bool
 }
 
 // BindFolds binds the given folds to have synchronized fold and reveal states.
-// The first fold is used as the basis for the width.
-func BindFolds(folds ...*Fold) {
-	for _, fold := range folds[1:] {
-		fold.SetShouldFoldFunc(func() bool { return folds[0].fold })
+// The first fold is used as the basis for the width. Folds with a FoldAlways or
+// FoldNever policy keep folding according to their own policy.
+func BindFolds(folds ...*Fold) *FoldGroup {
+	g := &FoldGroup{saved: make(map[*Fold]func() bool, len(folds))}
+	for _, fold := range folds {
+		g.saved[fold] = fold.shouldFold
 	}
+	g.folds = append(g.folds, folds...)
+	g.bind()
+	return g
+}
 
-	folds[0].NotifyFolded(func(bool) {
-		for _, fold := range folds[1:] {
-			fold.updateLayout()
-		}
-	})
+// Folds returns the folds in the group.
+func (g *FoldGroup) Folds() []*Fold {
+	return append([]*Fold(nil), g.folds...)
+}
+
+// Add adds the given fold into the group. It does nothing if the fold is
+// already in the group.
+func (g *FoldGroup) Add(fold *Fold) {
+	if _, ok := g.saved[fold]; ok {
+		return
+	}
+
+	g.unbind()
+	g.saved[fold] = fold.shouldFold
+	g.folds = append(g.folds, fold)
+	g.bind()
+}
 
-	var mutex bool
-	do := func(f func()) {
-		if !mutex {
-			mutex = true
-			f()
-			mutex = false
+// Remove removes the given fold from the group. The fold folds on its own
+// again afterwards. If the fold is the first one, then the next fold becomes
+// the basis for the width.
+func (g *FoldGroup) Remove(fold *Fold) {
+	shouldFold, ok := g.saved[fold]
+	if !ok {
+		return
+	}
+
+	g.unbind()
+
+	for i, f := range g.folds {
+		if f == fold {
+			g.folds = append(g.folds[:i], g.folds[i+1:]...)
+			break
 		}
 	}
 
+	delete(g.saved, fold)
+	fold.shouldFold = shouldFold
+	fold.updateLayout()
+
+	g.bind()
+}
+
+// Unbind unbinds all folds in the group from each other. The group is empty
+// afterwards.
+func (g *FoldGroup) Unbind() {
+	g.unbind()
+
+	for _, fold := range g.folds {
+		fold.shouldFold = g.saved[fold]
+		fold.updateLayout()
+	}
+
+	g.folds = nil
+	g.saved = make(map[*Fold]func() bool)
+}
+
+func (g *FoldGroup) bind() {
+	if len(g.folds) == 0 {
+		return
+	}
+
+	leader := g.folds[0]
+	leader.shouldFold = g.saved[leader]
+
+	for _, fold := range g.folds[1:] {
+		fold.SetShouldFoldFunc(func() bool { return leader.fold })
+	}
+
+	g.unbinds = append(g.unbinds, leader.NotifyFolded(func(bool) {
+		for _, fold := range g.folds[1:] {
+			fold.updateLayout()
+		}
+	}))
+
 	// We need to do this because we want to unreveal everything if even one
 	// fold gets collapsed.
-	for i := range folds {
-		i := i
-		folds[i].NotifyRevealed(func(revealed bool) {
-			do(func() {
-				for j, fold := range folds {
-					if i == j {
+	for _, fold := range g.folds {
+		fold := fold
+		g.unbinds = append(g.unbinds, fold.NotifyRevealed(func(revealed bool) {
+			g.do(func() {
+				for _, other := range g.folds {
+					if other == fold {
 						continue
 					}
-					fold.reveal = revealed
-					fold.doRevealSide()
+					other.reveal = revealed
+					other.doRevealSide()
 				}
 			})
-		})
+		}))
+	}
+}
+
+func (g *FoldGroup) unbind() {
+	for _, unbind := range g.unbinds {
+		unbind()
+	}
+	g.unbinds = nil
+}
+
+func (g *FoldGroup) do(f func()) {
+	if !g.mutex {
+		g.mutex = true
+		f()
+		g.mutex = false
 	}
 }
 
@@ -110,21 +214,135 @@ type Fold struct {
 	dimming    *gtk.Box
 	siderev    *gtk.Revealer
 	sidebox    *Bin
+	railbox    *Bin
+	handle     *gtk.Box
+	hint       *gtk.Box
 	contentbox *gtk.Overlay
 
-	onFold     func(bool)
+	actions      *gio.SimpleActionGroup
+	revealAction *gio.SimpleAction
+
+	onFold     callbacks
+	onReveal   callbacks
+	onResize   callbacks
 	shouldFold func() bool
+	fcond      BreakpointCondition
+	// fsizeRef is the widget whose size is matched against the fold
+	// condition. It is the fold itself if nil.
+	fsizeRef *gtk.Widget
+
+	fpos    gtk.PositionType
+	fpolicy FoldPolicy
+	ftrans  FoldTransition
+	fthres  int
+	fwidth  int
+	dwidth  int // docked width, including the user's resizing
+	owidth  FoldOverlayWidth
+	rthres  int
+	sedge   int
+	hsize   int
+	hedge   int
+	// hinter is only added to the fold while there is a reveal hint.
+	hinter *gtk.GestureDrag
+
+	resizable bool
+	resizeMin int
+	resizeMax int
+
+	fold     bool
+	rail     bool
+	reveal   bool
+	revealed bool
+	modal    bool
+	// hovered is true if the sidebar was revealed by hovering over the edge.
+	hovered bool
+	hoverID glib.SourceHandle
+
+	// refocus is the widget to give the focus back to once the modal sidebar
+	// is hidden.
+	refocus gtk.Widgetter
+
+	// progress is how far the folded sidebar is revealed, from 0 (hidden) to 1
+	// (fully revealed). It follows the user's finger while dragging.
+	progress float64
+	animID   uint
+	drag     foldDrag
+}
+
+type foldDrag struct {
+	x, y   float64
+	from   float64
+	active bool
+	denied bool
+}
+
+// FoldPolicy determines when a Fold folds its sidebar.
+type FoldPolicy int
+
+const (
+	// FoldAuto folds the sidebar when the fold becomes smaller than its fold
+	// threshold. It is the default policy.
+	FoldAuto FoldPolicy = iota
+	// FoldAlways always folds the sidebar, so it is only ever shown over the
+	// content.
+	FoldAlways
+	// FoldNever never folds the sidebar, so it is always shown next to the
+	// content.
+	FoldNever
+)
 
-	fpos   gtk.PositionType
-	fthres int
-	fwidth int
+// FoldTransition determines how the folded sidebar and the content move when
+// the sidebar is revealed.
+type FoldTransition int
 
-	fold   bool
-	reveal bool
+const (
+	// FoldTransitionOver slides the sidebar over the content. It is the
+	// default transition.
+	FoldTransitionOver FoldTransition = iota
+	// FoldTransitionUnder keeps the sidebar in place underneath the content
+	// while the content slides away to uncover it. The content gets the
+	// window's background color so that the sidebar doesn't show through.
+	FoldTransitionUnder
+	// FoldTransitionSlide slides the sidebar in and pushes the content away by
+	// the sidebar's size.
+	FoldTransitionSlide
+)
+
+func (t FoldTransition) cssClass() string {
+	switch t {
+	case FoldTransitionUnder:
+		return "adaptive-sidebar-transition-under"
+	case FoldTransitionSlide:
+		return "adaptive-sidebar-transition-slide"
+	default:
+		return "adaptive-sidebar-transition-over"
+	}
+}
+
+// FoldOverlayWidth calculates the width of the folded sidebar from the width
+// of the fold. For top and bottom folds, it calculates the height from the
+// height instead.
+type FoldOverlayWidth func(foldWidth int) int
+
+// FoldOverlayFixed gives the folded sidebar a fixed width.
+func FoldOverlayFixed(width int) FoldOverlayWidth {
+	return func(int) int { return width }
+}
+
+// FoldOverlayFraction gives the folded sidebar a fraction of the fold's width.
+func FoldOverlayFraction(fraction float64) FoldOverlayWidth {
+	return func(w int) int { return int(math.Round(float64(w) * fraction)) }
+}
+
+// FoldOverlayMargin gives the folded sidebar the fold's width minus the given
+// margin, so that a bit of the content is always visible next to it.
+func FoldOverlayMargin(margin int) FoldOverlayWidth {
+	return func(w int) int { return w - margin }
 }
 
 // Fold threshold constants that determine when swiping velocities should be
-// handled.
+// handled. For top and bottom folds, the X threshold applies to the vertical
+// velocity and vice versa.
 var (
 	FoldXThreshold = [2]float64{800, math.Inf(+1)}
 	FoldYThreshold = [2]float64{0, 4000}
@@ -141,25 +359,87 @@ const (
 	defaultFoldThreshold = 400
 )
 
-// NewFold creates a new sidebar.
+// FoldRevealDuration is the duration in milliseconds of the animation that
+// reveals or hides the folded sidebar.
+const FoldRevealDuration = 250
+
+// FoldHoverDelay is the time in milliseconds that the pointer must rest on the
+// fold's edge before the folded sidebar is revealed. See SetRevealOnHover.
+const FoldHoverDelay = 300
+
+// foldDragThreshold is the distance in pixels that the user's finger must move
+// before a drag is recognized as either a horizontal swipe or not.
+const foldDragThreshold = 8
+
+// NewFold creates a new sidebar. Left and right positions are mirrored for
+// right-to-left text directions, just like the children of a gtk.Box, so
+// gtk.PosLeft puts the sidebar on the right in those locales. Top and bottom
+// positions give a panel or a bottom sheet that slides in vertically.
 func NewFold(position gtk.PositionType) *Fold {
+	switch position {
+	case gtk.PosLeft, gtk.PosRight, gtk.PosTop, gtk.PosBottom:
+		return newFold(position)
+	default:
+		log.Panicln("invalid position given:", position)
+		return nil
+	}
+}
+
+// NewFoldPacked creates a new sidebar that is packed at the start or the end of
+// the fold. It is the same as NewFold with gtk.PosLeft or gtk.PosRight, so
+// gtk.PackStart puts the sidebar on the right in right-to-left locales.
+func NewFoldPacked(pack gtk.PackType) *Fold {
+	if pack == gtk.PackEnd {
+		return newFold(gtk.PosRight)
+	}
+	return newFold(gtk.PosLeft)
+}
+
+func newFold(position gtk.PositionType) *Fold {
 	f := &Fold{
 		fpos:   position,
 		fthres: defaultFoldThreshold,
 		fwidth: defaultFoldWidth,
 		fold:   false,
+		modal:  true,
+
+		resizeMin: defaultFoldWidth / 2,
+		resizeMax: defaultFoldWidth * 2,
+		// The sidebar is revealed while unfolded.
+		revealed: true,
 	}
 
+	vertical := f.vertical()
+
 	f.sidebox = NewBin()
-	f.sidebox.SetSizeRequest(f.fwidth, -1)
 	f.sidebox.AddCSSClass("adaptive-sidebar-side")
-	f.sidebox.SetVExpand(true)
+	f.sidebox.SetVExpand(!vertical)
+	f.sidebox.SetHExpand(vertical)
+	f.setSideSize(f.fwidth)
 
 	f.siderev = gtk.NewRevealer()
 	f.siderev.AddCSSClass("adaptive-sidebar-revealer")
 	f.siderev.SetChild(f.sidebox)
-	f.siderev.SetVExpand(true)
-	f.siderev.SetHExpand(false)
+	f.siderev.SetVExpand(!vertical)
+	f.siderev.SetHExpand(vertical)
+
+	f.handle = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	f.handle.AddCSSClass("adaptive-sidebar-handle")
+	f.handle.SetVisible(false)
+	if vertical {
+		f.handle.SetCursorFromName("row-resize")
+	} else {
+		f.handle.SetCursorFromName("col-resize")
+	}
+
+	f.railbox = NewBin()
+	f.railbox.AddCSSClass("adaptive-sidebar-rail")
+	f.railbox.SetVisible(false)
+
+	f.hint = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	f.hint.AddCSSClass("adaptive-sidebar-hint")
+	f.hint.SetCursorFromName("pointer")
+	f.hint.SetVisible(false)
 
 	f.dimming = gtk.NewBox(gtk.OrientationHorizontal, 0)
 	f.dimming.AddCSSClass("adaptive-sidebar-dimming")
@@ -176,32 +456,37 @@ func NewFold(position gtk.PositionType) *Fold {
 	f.contentbox.SetVExpand(true)
 	f.contentbox.SetHExpand(true)
 
-	f.main = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	// The content always stays in f.main, so that it keeps its focus and
+	// doesn't get unrealized when the fold folds. Only the sidebar moves out
+	// of it.
+	f.main = gtk.NewBox(f.orientation(), 0)
 	f.main.SetVExpand(true)
+	f.main.Append(f.contentbox)
 
-	switch position {
-	case gtk.PosLeft:
-		f.siderev.SetHAlign(gtk.AlignStart)
-		f.siderev.SetTransitionType(gtk.RevealerTransitionTypeSlideRight)
-		f.main.Append(f.siderev)
-		f.main.Append(f.contentbox)
-	case gtk.PosRight:
-		f.siderev.SetHAlign(gtk.AlignEnd)
-		f.siderev.SetTransitionType(gtk.RevealerTransitionTypeSlideLeft)
-		f.main.Append(f.contentbox)
-		f.main.Append(f.siderev)
-	default:
-		log.Panicln("invalid position given:", position)
-	}
-
+	// f.main is an overlay rather than the child, so that it can be moved
+	// along with the sidebar.
 	f.overlay = gtk.NewOverlay()
-	f.overlay.SetChild(f.main)
+	f.overlay.AddOverlay(f.main)
+	f.overlay.SetMeasureOverlay(f.main, true)
 	f.overlay.AddCSSClass("adaptive-sidebar")
+	f.overlay.AddCSSClass(f.ftrans.cssClass())
+	f.overlay.AddCSSClass("adaptive-sidebar-modal")
 	f.overlay.SetVExpand(true)
+	// Clip the content in case it's being slid out of the fold.
+	f.overlay.SetOverflow(gtk.OverflowHidden)
+	f.overlay.ConnectGetChildPosition(f.childPosition)
+	f.overlay.ConnectDirectionChanged(func(gtk.TextDirection) {
+		f.placeSide()
+		f.overlay.QueueAllocate()
+	})
+
+	f.placeSide()
 
 	f.Widget = gtk.BaseWidget(f.overlay)
-	f.shouldFold = func() bool { return f.overlay.AllocatedWidth() < f.fthres }
-	f.bind()
+	f.shouldFold = f.matchesFoldCondition
+	// Fold according to our own allocation rather than the window's, so that
+	// the fold also works inside a gtk.Paned or next to another fold.
+	watchSize(f.overlay, func(int, int) { f.updateLayout() })
 	f.updateLayout()
 
 	// Bind handlers that will blur the content box if the revealer is over it.
@@ -212,49 +497,504 @@ func NewFold(position gtk.PositionType) *Fold {
 	bgclicker := gtk.NewGestureClick()
 	bgclicker.SetExclusive(true)
 	bgclicker.ConnectPressed(func(n int, x, y float64) {
-		if f.fold && f.siderev.RevealChild() {
-			f.siderev.SetRevealChild(false)
+		if f.fold && f.revealed && f.modal {
+			f.SetRevealSide(false)
 		}
 	})
 	// Bind it to the main widget. Note that f.main will be underneath the
-	// revealer overlay, so we can assume that if it's clicked, it's ever going
-	// to be clicked outside the revealer.
+	// revealer overlay, and the content cannot be targeted while
+	// the modal sidebar is shown, so we can assume that if it's clicked, it's
+	// ever going to be clicked outside the revealer.
 	f.main.AddController(bgclicker)
 
-	// Controller for swiping.
+	// Controller for swiping. The sidebar follows the finger while dragging,
+	// then snaps open or closed once the finger is lifted.
 	swiper := gtk.NewGestureSwipe()
 	swiper.SetExclusive(true)
 	swiper.SetTouchOnly(true)
-	swiper.ConnectSwipe(func(velX, velY float64) {
+	swiper.ConnectBegin(func(sequence *gdk.EventSequence) {
 		if !f.fold {
+			swiper.SetState(gtk.EventSequenceDenied)
+			return
+		}
+		x, y, _ := swiper.Point(sequence)
+		if f.isHint(x, y) {
+			// The hint has its own gesture.
+			swiper.SetState(gtk.EventSequenceDenied)
+			return
+		}
+		if f.progress == 0 && !f.inSwipeEdge(x, y) {
+			// Leave swipes away from the edge to the content.
+			swiper.SetState(gtk.EventSequenceDenied)
+			return
+		}
+		f.drag = foldDrag{x: x, y: y, from: f.progress}
+	})
+	swiper.ConnectUpdate(func(sequence *gdk.EventSequence) {
+		x, y, ok := swiper.Point(sequence)
+		if !ok {
+			return
+		}
+
+		active := f.drag.active
+		if !f.updateDrag(x-f.drag.x, y-f.drag.y) {
+			swiper.SetState(gtk.EventSequenceDenied)
 			return
 		}
-		if isInThreshold(velX, FoldXThreshold) && isInThreshold(velY, FoldYThreshold) {
-			// Determine the orientation of the swiping by inspecting the sign
-			// of the X (horizontal) velocity.
-			// Negative is right-to-left, and positive is left-to-right.
-			f.siderev.SetRevealChild(velX > 0)
+		if !active && f.drag.active {
+			swiper.SetState(gtk.EventSequenceClaimed)
+		}
+	})
+	swiper.ConnectSwipe(func(velX, velY float64) {
+		f.endDrag(velX, velY)
+	})
+	swiper.ConnectCancel(func(*gdk.EventSequence) {
+		if f.drag.active {
+			f.drag.active = false
+			// Snap back to wherever we were.
+			f.doRevealSide()
 		}
 	})
 	f.overlay.AddController(swiper)
 
+	// Controller for swiping with two fingers on a touchpad. Only touchpads
+	// emit scroll-begin, so mouse wheels are left alone. It runs in the capture
+	// phase so that scrollable content doesn't take the swipe first, which is
+	// why it only starts on the swipe edge like the swiper. Scroll events have
+	// no position, so the pointer's last one is used.
+	var scrolling bool
+	var pointerX, pointerY float64
+	var scrollEnd glib.SourceHandle
+	scroller := gtk.NewEventControllerScroll(
+		gtk.EventControllerScrollBothAxes | gtk.EventControllerScrollKinetic)
+	scroller.SetPropagationPhase(gtk.PhaseCapture)
+	scroller.ConnectScrollBegin(func() {
+		if !f.fold || (f.progress == 0 && !f.inSwipeEdge(pointerX, pointerY)) {
+			return
+		}
+		scrolling = true
+		f.drag = foldDrag{from: f.progress}
+	})
+	scroller.ConnectScroll(func(dx, dy float64) bool {
+		if !scrolling || f.drag.denied {
+			return false
+		}
+		// The scroll deltas move the content, so the fingers move the other
+		// way. x and y add up how far they moved.
+		f.drag.x -= dx
+		f.drag.y -= dy
+		if !f.updateDrag(f.drag.x, f.drag.y) {
+			f.drag.denied = true
+			return false
+		}
+		return f.drag.active
+	})
+	scroller.ConnectDecelerate(func(velX, velY float64) {
+		if scrolling {
+			scrolling = false
+			f.endDrag(-velX, -velY)
+		}
+	})
+	scroller.ConnectScrollEnd(func() {
+		if !scrolling || scrollEnd != 0 {
+			return
+		}
+		// GTK emits decelerate with the final velocity after scroll-end, so
+		// only settle without it if it never comes.
+		scrollEnd = glib.IdleAdd(func() {
+			scrollEnd = 0
+			if scrolling {
+				scrolling = false
+				f.endDrag(0, 0)
+			}
+		})
+	})
+	f.overlay.AddController(scroller)
+
+	// Controller for revealing the sidebar by hovering over the edge.
+	hover := gtk.NewEventControllerMotion()
+	hover.ConnectEnter(func(x, y float64) {
+		pointerX, pointerY = x, y
+	})
+	hover.ConnectMotion(func(x, y float64) {
+		pointerX, pointerY = x, y
+		if device := hover.CurrentEventDevice(); device != nil {
+			if gdk.BaseDevice(device).Source() == gdk.SourceTouchscreen {
+				// Touch input has no hover; it uses swiping instead.
+				return
+			}
+		}
+		f.handleHover(x, y)
+	})
+	hover.ConnectLeave(func() {
+		f.stopHover()
+		if f.hovered {
+			f.SetRevealSide(false)
+		}
+	})
+	f.overlay.AddController(hover)
+
+	// Controller for clicking or dragging the reveal hint. Like the resizer,
+	// it is bound to the overlay, since the hint moves along with the sidebar
+	// while it's being dragged.
+	var hintFrom float64
+	var hinting bool
+	hinter := gtk.NewGestureDrag()
+	hinter.ConnectDragBegin(func(x, y float64) {
+		if !f.fold || f.revealed || !f.isHint(x, y) {
+			hinter.SetState(gtk.EventSequenceDenied)
+			return
+		}
+		hinter.SetState(gtk.EventSequenceClaimed)
+		hinting = true
+		f.stopAnimation()
+		hintFrom = f.progress
+	})
+	hinter.ConnectDragUpdate(func(x, y float64) {
+		if !hinting {
+			return
+		}
+		along := x
+		if f.vertical() {
+			along = y
+		}
+		progress := hintFrom + f.dragDirection()*along/float64(f.sideSize())
+		f.setProgress(math.Max(0, math.Min(1, progress)))
+	})
+	hinter.ConnectDragEnd(func(x, y float64) {
+		// GTK also ends drags that were denied, which aren't ours.
+		if !hinting {
+			return
+		}
+		hinting = false
+		if math.Abs(x) < foldDragThreshold && math.Abs(y) < foldDragThreshold {
+			// Treat it as a click.
+			f.SetRevealSide(true)
+			return
+		}
+		f.SetRevealSide(f.progress >= 0.5)
+	})
+	f.hinter = hinter
+
+	// Controller for resizing the unfolded sidebar using the handle. It is
+	// bound to f.main rather than the handle, since the handle moves while
+	// it's being dragged.
+	var resizeFrom int
+	var resizing bool
+	resizer := gtk.NewGestureDrag()
+	resizer.ConnectDragBegin(func(x, y float64) {
+		target := f.main.Pick(x, y, gtk.PickDefault)
+		if !f.resizable || f.fold || target == nil || !glib.ObjectEq(target, f.handle) {
+			resizer.SetState(gtk.EventSequenceDenied)
+			return
+		}
+		resizer.SetState(gtk.EventSequenceClaimed)
+		resizing = true
+		resizeFrom = f.FoldWidth()
+	})
+	resizer.ConnectDragUpdate(func(x, y float64) {
+		if !resizing {
+			return
+		}
+		along := x
+		if f.vertical() {
+			along = y
+		}
+		f.resizeSide(resizeFrom + int(math.Round(f.dragDirection()*along)))
+	})
+	resizer.ConnectDragEnd(func(x, y float64) {
+		// GTK also ends drags that were denied, which aren't ours.
+		if !resizing {
+			return
+		}
+		resizing = false
+		// Only refold once the user is done, so the sidebar doesn't get
+		// folded away from under the pointer.
+		f.updateLayout()
+		f.onResize.call()
+	})
+	f.main.AddController(resizer)
+
+	// Controller for keyboard navigation in the modal sidebar. It captures
+	// the keys before the focused widget gets them.
+	keys := gtk.NewEventControllerKey()
+	keys.SetPropagationPhase(gtk.PhaseCapture)
+	keys.ConnectKeyPressed(f.handleKey)
+	f.overlay.AddController(keys)
+
+	f.revealAction = f.newRevealAction(FoldRevealAction)
+	f.actions = gio.NewSimpleActionGroup()
+	f.actions.AddAction(f.revealAction)
+	f.overlay.InsertActionGroup("fold", f.actions)
+
 	return f
 }
 
+// ActionGroup returns the fold's action group, which contains the
+// FoldRevealAction. It is already inserted into the fold as "fold", but it can
+// also be inserted into the window so that the action works from anywhere in
+// it. An accelerator can then be bound to it, e.g.:
+//
+//	window.InsertActionGroup("fold", fold.ActionGroup())
+//	app.SetAccelsForAction("fold.reveal-sidebar", []string{"F9"})
+func (f *Fold) ActionGroup() *gio.SimpleActionGroup {
+	return f.actions
+}
+
+// newRevealAction creates an action with the given name for toggling the
+// sidebar from menus and accelerators. It's only enabled while folded, just
+// like FoldRevealButton.
+func (f *Fold) newRevealAction(name string) *gio.SimpleAction {
+	action := gio.NewSimpleActionStateful(name, nil, glib.NewVariantBoolean(f.revealed))
+	action.ConnectChangeState(func(state *glib.Variant) {
+		f.SetRevealSide(state.Boolean())
+	})
+	f.NotifyRevealed(func(revealed bool) {
+		action.SetState(glib.NewVariantBoolean(revealed))
+	})
+	f.NotifyFolded(func(folded bool) {
+		action.SetEnabled(folded)
+	})
+	return action
+}
+
+// updateDrag moves the folded sidebar along with a drag that has gone the
+// given distance since it started. It returns false if the drag goes across
+// the sidebar's sliding axis or away from where the sidebar can go, in which
+// case it is for the content instead.
+func (f *Fold) updateDrag(dx, dy float64) bool {
+	// along is the distance along the sidebar's sliding axis, and across is
+	// the distance perpendicular to it.
+	along, across := dx, dy
+	if f.vertical() {
+		along, across = across, along
+	}
+
+	if !f.drag.active {
+		if math.Abs(along) < foldDragThreshold && math.Abs(across) < foldDragThreshold {
+			return true
+		}
+		if math.Abs(across) > math.Abs(along) {
+			return false
+		}
+		if dir := along * f.dragDirection(); f.drag.from == 0 && dir < 0 || f.drag.from == 1 && dir > 0 {
+			// The sidebar can't go any further this way, so leave the drag
+			// to someone else, such as the other sidebar of a DualFold.
+			return false
+		}
+		f.drag.active = true
+		f.stopAnimation()
+	}
+
+	progress := f.drag.from + f.dragDirection()*along/float64(f.sideSize())
+	f.setProgress(math.Max(0, math.Min(1, progress)))
+	return true
+}
+
+// endDrag snaps the folded sidebar open or closed once a drag ends with the
+// given velocity.
+func (f *Fold) endDrag(velX, velY float64) {
+	if !f.drag.active {
+		return
+	}
+	f.drag.active = false
+
+	if f.vertical() {
+		velX, velY = velY, velX
+	}
+
+	reveal := f.progress >= 0.5
+	if isInThreshold(velX, FoldXThreshold) && isInThreshold(velY, FoldYThreshold) {
+		// Determine the orientation of the swiping by inspecting the sign of
+		// the velocity along the sliding axis relative to the sidebar.
+		reveal = velX*f.dragDirection() > 0
+	}
+
+	f.SetRevealSide(reveal)
+}
+
 // SetWidthFunc sets the function to get the width to determine the fold
 // threshold.
 func (f *Fold) SetWidthFunc(widthFunc func() int) {
 	f.shouldFold = func() bool { return widthFunc() < f.fthres }
 }
 
+// SetFoldCondition sets the condition on the fold's own size that determines
+// whether or not the fold should be folded. It overrides SetFoldThreshold,
+// SetWidthFunc and SetShouldFoldFunc. If cond is nil, then the fold threshold
+// is used again.
+func (f *Fold) SetFoldCondition(cond BreakpointCondition) {
+	f.fcond = cond
+	f.shouldFold = f.matchesFoldCondition
+	f.updateLayout()
+}
+
+// FoldCondition returns the condition that determines whether or not the fold
+// should be folded. If no condition was set, then the returned condition
+// matches a size below the fold threshold.
+func (f *Fold) FoldCondition() BreakpointCondition {
+	if f.fcond != nil {
+		return f.fcond
+	}
+
+	// Move the threshold along with the width that the user resized the
+	// sidebar to.
+	thres := f.fthres + f.FoldWidth() - f.fwidth
+
+	if f.vertical() {
+		return BreakpointMaxHeight(thres - 1)
+	}
+	return BreakpointMaxWidth(thres - 1)
+}
+
+func (f *Fold) matchesFoldCondition() bool {
+	ref := f.fsizeRef
+	if ref == nil {
+		ref = gtk.BaseWidget(f.overlay)
+	}
+	return f.FoldCondition().Matches(ref.AllocatedWidth(), ref.AllocatedHeight())
+}
+
 // SetShouldFoldFunc sets the callback to determine whether or not fold should
 // be folded. It overrides SetWidthFunc.
 func (f *Fold) SetShouldFoldFunc(shouldFold func() bool) {
 	f.shouldFold = shouldFold
 }
 
+// SetSwipeEdge sets the size of the region along the sidebar's edge of the
+// fold where a swipe can start to reveal the folded sidebar, such as 24 pixels.
+// Swipes that start elsewhere are left to the content. Swipes that close the
+// revealed sidebar can start anywhere. If edge is 0 or less, which is the
+// default, then revealing swipes can start anywhere as well.
+func (f *Fold) SetSwipeEdge(edge int) {
+	f.sedge = edge
+}
+
+// SwipeEdge returns the size of the swipe edge region.
+func (f *Fold) SwipeEdge() int {
+	return f.sedge
+}
+
+// inSwipeEdge returns true if the given point within the fold is inside the
+// region where a revealing swipe can start.
+func (f *Fold) inSwipeEdge(x, y float64) bool {
+	if f.sedge <= 0 {
+		return true
+	}
+
+	return f.edgeDistance(x, y) <= float64(f.sedge)
+}
+
+// edgeDistance returns the distance of the given point within the fold from
+// the edge of the sidebar's side.
+func (f *Fold) edgeDistance(x, y float64) float64 {
+	switch f.side() {
+	case gtk.PosLeft:
+		return x
+	case gtk.PosRight:
+		return float64(f.overlay.AllocatedWidth()) - x
+	case gtk.PosTop:
+		return y
+	default:
+		return float64(f.overlay.AllocatedHeight()) - y
+	}
+}
+
+// SetRevealOnHover sets the size of the region along the sidebar's edge of the
+// fold where resting the pointer reveals the folded sidebar after
+// FoldHoverDelay. The sidebar is hidden again once the pointer leaves it. This
+// is meant for pointer users and does not apply to touch input. Sidebars
+// revealed this way don't take the keyboard focus. If edge is 0 or less, which
+// is the default, then hovering doesn't reveal the sidebar.
+func (f *Fold) SetRevealOnHover(edge int) {
+	f.hedge = edge
+	if edge <= 0 {
+		f.stopHover()
+	}
+}
+
+// RevealOnHover returns the size of the hover region.
+func (f *Fold) RevealOnHover() int {
+	return f.hedge
+}
+
+func (f *Fold) handleHover(x, y float64) {
+	if f.hedge <= 0 || !f.fold {
+		return
+	}
+
+	dist := f.edgeDistance(x, y)
+
+	if f.revealed {
+		if f.hovered && dist > float64(f.railSize()+f.sideSize()) {
+			// The pointer left the sidebar.
+			f.SetRevealSide(false)
+		}
+		return
+	}
+
+	if dist > float64(f.hedge) {
+		f.stopHover()
+		return
+	}
+
+	if f.hoverID != 0 {
+		return
+	}
+
+	f.hoverID = glib.TimeoutAdd(FoldHoverDelay, func() {
+		f.hoverID = 0
+		if f.fold && !f.revealed {
+			f.hovered = true
+			f.SetRevealSide(true)
+		}
+	})
+}
+
+func (f *Fold) stopHover() {
+	if f.hoverID != 0 {
+		glib.SourceRemove(f.hoverID)
+		f.hoverID = 0
+	}
+}
+
+// SetRevealHint sets the size of the hint that peeks out from the sidebar's
+// edge of the fold while the folded sidebar is hidden, so that the user can
+// tell that there is a sidebar. Clicking the hint reveals the sidebar, and
+// dragging it pulls the sidebar out. The hint has the CSS class
+// "adaptive-sidebar-hint". If size is 0 or less, which is the default, then no
+// hint is shown.
+func (f *Fold) SetRevealHint(size int) {
+	switch {
+	case size > 0 && f.hsize <= 0:
+		f.overlay.AddController(f.hinter)
+	case size <= 0 && f.hsize > 0:
+		f.overlay.RemoveController(f.hinter)
+	}
+
+	f.hsize = size
+	f.updateState()
+	f.overlay.QueueAllocate()
+}
+
+// RevealHint returns the size of the reveal hint.
+func (f *Fold) RevealHint() int {
+	return f.hsize
+}
+
+// isHint returns true if the given point within the fold is on the reveal
+// hint.
+func (f *Fold) isHint(x, y float64) bool {
+	if !f.hint.Visible() {
+		return false
+	}
+	target := f.overlay.Pick(x, y, gtk.PickDefault)
+	return target != nil && (glib.ObjectEq(target, f.hint) || gtk.BaseWidget(target).IsAncestor(f.hint))
+}
+
 // SetFoldThreshold sets the width threshold that the sidebar will determine
-// whether or not to fold.
+// whether or not to fold. For top and bottom folds, the threshold is compared
+// against the height instead.
 func (f *Fold) SetFoldThreshold(threshold int) {
 	f.fthres = threshold
 	f.updateLayout()
@@ -266,17 +1006,102 @@ func (f *Fold) FoldThreshold() int {
 }
 
 // SetFoldWidth sets the width of the sidebar. The width must be lower than the
-// fold threshold.
+// fold threshold. For top and bottom folds, this is the height of the panel.
+// It also resets any width that the user has resized the sidebar to.
 func (f *Fold) SetFoldWidth(width int) {
-	f.sidebox.SetSizeRequest(width, -1)
+	f.fwidth = width
+	f.setSideSize(width)
+	f.updateLayout()
+}
+
+// SetResizable sets whether or not the user can resize the unfolded sidebar by
+// dragging a handle on its edge. The width is kept within the given minimum
+// and maximum. A maximum of 0 or less means no maximum. Once the sidebar is
+// no longer resizable, it goes back to the width given to SetFoldWidth.
+func (f *Fold) SetResizable(resizable bool, min, max int) {
+	f.resizable = resizable
+	f.resizeMin = min
+	f.resizeMax = max
+	f.handle.SetVisible(resizable && !f.fold)
+
+	if resizable {
+		f.resizeSide(f.FoldWidth())
+	} else {
+		f.setSideSize(f.fwidth)
+	}
 	f.updateLayout()
 }
 
+// IsResizable returns true if the user can resize the unfolded sidebar.
+func (f *Fold) IsResizable() bool {
+	return f.resizable
+}
+
+// SetResizedWidth sets the width of the sidebar as if the user had resized it,
+// which is useful for restoring a width given by NotifyResized. The width is
+// kept within the limits given to SetResizable. Unlike SetFoldWidth, the fold
+// threshold is moved along with the width, so the content keeps the same
+// amount of space before the fold folds.
+func (f *Fold) SetResizedWidth(width int) {
+	f.resizeSide(width)
+	f.updateLayout()
+}
+
+// resizeSide sets the width of the sidebar within the resize limits without
+// refolding, so that a drag can wait until it ends.
+func (f *Fold) resizeSide(width int) {
+	if width < f.resizeMin {
+		width = f.resizeMin
+	}
+	if f.resizeMax > 0 && width > f.resizeMax {
+		width = f.resizeMax
+	}
+	if width < 1 {
+		width = 1
+	}
+	f.setSideSize(width)
+}
+
+// NotifyResized subscribes fn to be called when the user is done resizing the
+// sidebar. fn is called with the new width, which can be saved and restored
+// with SetResizedWidth. The returned callback unsubscribes fn.
+func (f *Fold) NotifyResized(fn func(width int)) func() {
+	return f.onResize.add(func() { fn(f.FoldWidth()) })
+}
+
 // FoldWidth returns the width of the sidebar. It is calculated from the fold
 // threshold.
 func (f *Fold) FoldWidth() int {
-	w, _ := f.sidebox.SizeRequest()
-	return w
+	return f.dwidth
+}
+
+// SetFoldOverlayWidth sets the width of the sidebar while it's folded and
+// shown over the content. If width is nil, then the folded sidebar keeps the
+// same width as the docked one, which is the default. SetFoldWidth only
+// controls the docked width.
+func (f *Fold) SetFoldOverlayWidth(width FoldOverlayWidth) {
+	f.owidth = width
+	f.applySideSize()
+	f.overlay.QueueAllocate()
+}
+
+func (f *Fold) setSideSize(size int) {
+	f.dwidth = size
+	f.applySideSize()
+}
+
+func (f *Fold) applySideSize() {
+	size := f.dwidth
+	if f.fold && f.owidth != nil {
+		// The folded sidebar is sized by sideSize instead.
+		size = -1
+	}
+
+	if f.vertical() {
+		f.sidebox.SetSizeRequest(-1, size)
+	} else {
+		f.sidebox.SetSizeRequest(size, -1)
+	}
 }
 
 // SetSideChild sets the sidebar's side content.
@@ -289,11 +1114,78 @@ func (f *Fold) SetChild(child gtk.Widgetter) {
 	f.contentbox.SetChild(child)
 }
 
-// SetFolded sets whether or not the sidebar is folded.
+// SetFoldTransition sets how the sidebar and the content move when the folded
+// sidebar is revealed.
+func (f *Fold) SetFoldTransition(transition FoldTransition) {
+	f.overlay.RemoveCSSClass(f.ftrans.cssClass())
+	f.ftrans = transition
+	f.overlay.AddCSSClass(f.ftrans.cssClass())
+
+	f.restackSide()
+	f.overlay.QueueAllocate()
+}
+
+// FoldTransition returns the fold's current fold transition.
+func (f *Fold) FoldTransition() FoldTransition {
+	return f.ftrans
+}
+
+// SetRailChild sets the child that is shown as a navigation rail in place of
+// the sidebar while the fold is folded. The rail is usually a narrow column of
+// icons, and the full sidebar can still be revealed over it. If child is nil,
+// then the sidebar is hidden completely when folded.
+func (f *Fold) SetRailChild(child gtk.Widgetter) {
+	f.railbox.SetChild(child)
+	f.updateRail()
+}
+
+// SetRailThreshold sets the width threshold below which the rail is hidden as
+// well, so that the folded fold goes from showing the rail to showing nothing.
+// It should be lower than the fold threshold. By default, it is 0, so the rail
+// is shown whenever the fold is folded. For top and bottom folds, the
+// threshold is compared against the height instead.
+func (f *Fold) SetRailThreshold(threshold int) {
+	f.rthres = threshold
+	f.updateRail()
+}
+
+// RailThreshold returns the rail threshold.
+func (f *Fold) RailThreshold() int {
+	return f.rthres
+}
+
+// IsRail returns true if the fold is folded and currently showing its rail.
+func (f *Fold) IsRail() bool {
+	return f.rail
+}
+
+// SetFolded sets whether or not the sidebar is folded. With the FoldAuto
+// policy, the fold state is recalculated the next time the fold is resized. It
+// does nothing if the policy is FoldAlways or FoldNever; use SetFoldPolicy to
+// pin the fold state instead.
 func (f *Fold) SetFolded(folded bool) {
+	if f.fpolicy != FoldAuto {
+		return
+	}
 	f.setFold(folded)
 }
 
+// SetFoldPolicy sets the policy that determines when the sidebar is folded.
+func (f *Fold) SetFoldPolicy(policy FoldPolicy) {
+	f.fpolicy = policy
+	f.updateLayout()
+}
+
+// FoldPolicy returns the fold's current fold policy.
+func (f *Fold) FoldPolicy() FoldPolicy {
+	return f.fpolicy
+}
+
+// IsFolded returns true if the sidebar is currently folded.
+func (f *Fold) IsFolded() bool {
+	return f.fold
+}
+
 // SetRevealSide sets whether or not the sidebar is revealed. It does not
 // change if the sidebar isn't currently folded.
 func (f *Fold) SetRevealSide(reveal bool) {
@@ -303,97 +1195,191 @@ func (f *Fold) SetRevealSide(reveal bool) {
 
 func (f *Fold) doRevealSide() {
 	reveal := f.reveal || !f.fold
-	f.siderev.SetRevealChild(reveal)
+
+	changed := f.revealed != reveal
+	f.revealed = reveal
+	if !reveal || !f.fold {
+		f.hovered = false
+	}
+
+	if f.fold {
+		if reveal {
+			// Map the sidebar right away so that it can take the focus.
+			f.siderev.SetRevealChild(true)
+			f.animateProgress(1)
+		} else {
+			f.animateProgress(0)
+		}
+	} else {
+		f.stopAnimation()
+		f.progress = 1
+		f.siderev.SetRevealChild(true)
+	}
+
+	if changed {
+		if f.fold && f.modal && !f.hovered {
+			f.moveFocus(reveal)
+		}
+		f.onReveal.call()
+	}
 }
 
-// SideIsRevealed returns true if the sidebar is revealed. If the sidebar is not
-// folded, then true is returned regardless of what's given into SetRevealSide.
-func (f *Fold) SideIsRevealed() bool {
-	return f.siderev.RevealChild()
+// SetModal sets whether or not the folded sidebar is modal. A modal sidebar
+// takes the keyboard focus when it is revealed and keeps Tab navigation inside
+// itself until it is hidden, which the Escape key also does. The focus is then
+// given back to the widget that had it before, which is usually the
+// FoldRevealButton. While a modal sidebar is revealed, the content is dimmed
+// and clicking it hides the sidebar.
+//
+// A non-modal sidebar, such as a tool palette, leaves the content undimmed
+// and interactive, so it stays revealed until it is hidden explicitly. Folds
+// are modal by default.
+func (f *Fold) SetModal(modal bool) {
+	f.modal = modal
+	if !modal {
+		f.refocus = nil
+	}
+
+	if modal {
+		f.overlay.AddCSSClass("adaptive-sidebar-modal")
+	} else {
+		f.overlay.RemoveCSSClass("adaptive-sidebar-modal")
+	}
+
+	f.updateState()
 }
 
-// NotifyRevealed subscribes fn to be called if the sidebar is revealed or not.
-func (f *Fold) NotifyRevealed(fn func(revealed bool)) {
-	f.siderev.Connect("notify::reveal-child", func() {
-		fn(f.siderev.RevealChild())
-	})
+// IsModal returns true if the folded sidebar is modal.
+func (f *Fold) IsModal() bool {
+	return f.modal
 }
 
-// NotifyFolded subscribes fn to be called if the sidebar is folded or unfolded.
-func (f *Fold) NotifyFolded(fn func(folded bool)) {
-	defer f.notifyFolded()
+// moveFocus moves the keyboard focus into the sidebar when it is revealed, and
+// back to where it was once the sidebar is hidden again.
+func (f *Fold) moveFocus(revealed bool) {
+	root := f.overlay.Root()
+	if root == nil {
+		return
+	}
 
-	if f.onFold == nil {
-		f.onFold = fn
+	if revealed {
+		f.refocus = root.Focus()
+		if !f.sidebox.ChildFocus(gtk.DirTabForward) {
+			f.sidebox.GrabFocus()
+		}
 		return
 	}
 
-	old := f.onFold
-	f.onFold = func(folded bool) {
-		old(folded)
-		fn(folded)
+	refocus := f.refocus
+	f.refocus = nil
+
+	// Only give the focus back if it's still inside the sidebar, since the
+	// user might have moved it elsewhere already.
+	focus := root.Focus()
+	if refocus != nil && (focus == nil || gtk.BaseWidget(focus).IsAncestor(f.sidebox)) {
+		gtk.BaseWidget(refocus).GrabFocus()
 	}
 }
 
+func (f *Fold) handleKey(keyval, keycode uint, state gdk.ModifierType) bool {
+	if !f.modal || !f.fold || !f.revealed {
+		return false
+	}
+
+	switch keyval {
+	case gdk.KEY_Escape:
+		f.SetRevealSide(false)
+		return true
+
+	case gdk.KEY_Tab, gdk.KEY_KP_Tab, gdk.KEY_ISO_Left_Tab:
+		if f.hovered {
+			// The sidebar was only peeked at, so don't trap the focus.
+			return false
+		}
+
+		dir := gtk.DirTabForward
+		if keyval == gdk.KEY_ISO_Left_Tab || state&gdk.ShiftMask != 0 {
+			dir = gtk.DirTabBackward
+		}
+
+		if !f.sidebox.ChildFocus(dir) {
+			// Wrap around to the other end of the sidebar.
+			if root := f.overlay.Root(); root != nil {
+				root.SetFocus(nil)
+			}
+			f.sidebox.ChildFocus(dir)
+		}
+		return true
+	}
+
+	return false
+}
+
+// SideIsRevealed returns true if the sidebar is revealed. If the sidebar is not
+// folded, then true is returned regardless of what's given into SetRevealSide.
+func (f *Fold) SideIsRevealed() bool {
+	return f.revealed
+}
+
+// NotifyRevealed subscribes fn to be called if the sidebar is revealed or not.
+// The returned callback unsubscribes fn.
+func (f *Fold) NotifyRevealed(fn func(revealed bool)) func() {
+	return f.onReveal.add(func() { fn(f.revealed) })
+}
+
+// NotifyFolded subscribes fn to be called if the sidebar is folded or unfolded.
+// fn is also called once with the current fold state. The returned callback
+// unsubscribes fn.
+func (f *Fold) NotifyFolded(fn func(folded bool)) func() {
+	remove := f.onFold.add(func() { fn(f.fold) })
+	fn(f.fold)
+	return remove
+}
+
 func (f *Fold) notifyFolded() {
 	if f.fold {
 		f.overlay.AddCSSClass("adaptive-sidebar-folded")
 	} else {
 		f.overlay.RemoveCSSClass("adaptive-sidebar-folded")
 	}
-	if f.onFold != nil {
-		f.onFold(f.fold)
-	}
+	f.onFold.call()
 }
 
-// QueueResize should be called when Fold's parent widths are changed.
+// QueueResize rechecks whether or not the fold should be folded and queues a
+// resize on it. The fold already does this whenever its own size changes, but
+// this should be called if the function given to SetWidthFunc or
+// SetShouldFoldFunc depends on something else that has changed.
 func (f *Fold) QueueResize() {
 	f.updateLayout()
 	gtk.BaseWidget(f).QueueResize()
 }
 
-func (f *Fold) bind() {
-	var handle glib.SignalHandle
-	var surface *gdk.Surface
-
-	w := f.overlay
-
-	// Hack to resize the first time the widget has a size.
-	w.AddTickCallback(func(gtk.Widgetter, gdk.FrameClocker) bool {
-		if f.AllocatedWidth() > 0 {
-			f.updateLayout()
-			return false
-		}
-		// Retry on the next frame.
-		return true
-	})
-
-	w.ConnectRealize(func() {
-		// TODO: this doesn't cover the page where the inside is changed without
-		// the window being resized. It might be worth it to have a slow path
-		// that checks the width and updates the size every 1000/30ms or so.
-		surface = gdk.BaseSurface(w.Native().Surface())
-		handle = surface.Connect("notify::width", func() { f.updateLayout() })
-	})
-
-	w.ConnectUnrealize(func() {
-		surface.HandlerDisconnect(handle)
-		surface = nil
-	})
-}
-
 func (f *Fold) updateLayout() {
-	f.setFold(f.shouldFold())
+	switch f.fpolicy {
+	case FoldAlways:
+		f.setFold(true)
+	case FoldNever:
+		f.setFold(false)
+	default:
+		f.setFold(f.shouldFold())
+	}
+	f.updateRail()
 }
 
 func (f *Fold) updateState() {
 	reveal := f.siderev.RevealChild()
 
-	// If we're folded, then the user shouldn't be able to target the
-	// content box behind the revealer.
-	f.contentbox.SetCanTarget(!f.fold || !reveal)
-	// Only show the dimming overlay if we're folded.
-	f.dimming.SetVisible(f.fold)
+	// If we're folded and modal, then the user shouldn't be able to target
+	// the content box behind the revealer.
+	f.contentbox.SetCanTarget(!f.fold || !reveal || !f.modal)
+	// Only show the dimming overlay if we're folded and modal. It darkens as
+	// the sidebar is being revealed.
+	f.dimming.SetVisible(f.fold && f.modal)
+	f.dimming.SetOpacity(f.progress)
+	// The hint fades away as the sidebar comes out.
+	f.hint.SetVisible(f.fold && f.hsize > 0)
+	f.hint.SetOpacity(1 - f.progress)
+	f.hint.SetCanTarget(!f.revealed)
 
 	if reveal {
 		f.overlay.AddCSSClass("adaptive-sidebar-open")
@@ -409,22 +1395,331 @@ func (f *Fold) setFold(fold bool) {
 	f.fold = fold
 
 	if fold {
+		// The folded sidebar is moved by its reveal progress instead of the
+		// revealer's own animation.
+		f.progress = 0
+		f.siderev.SetRevealChild(false)
+		f.siderev.SetTransitionType(gtk.RevealerTransitionTypeNone)
+		f.applySideSize()
+
+		// The sidebar becomes an overlay so that it can be moved
+		// independently of the content.
 		f.main.Remove(f.siderev)
+		f.handle.SetVisible(false)
+		f.overlay.AddOverlay(f.hint)
 		f.overlay.AddOverlay(f.siderev)
 		f.overlay.SetMeasureOverlay(f.siderev, true)
+		f.restackSide()
 
 		f.doRevealSide()
+		f.updateRail()
 		f.notifyFolded()
 	} else {
 		f.overlay.RemoveOverlay(f.siderev)
-		switch f.fpos {
-		case gtk.PosLeft:
+		f.overlay.RemoveOverlay(f.hint)
+		f.handle.SetVisible(f.resizable)
+		f.placeSide()
+		f.applySideSize()
+
+		f.doRevealSide()
+		f.updateRail()
+		f.notifyFolded()
+	}
+}
+
+// updateRail shows the rail if the fold is folded but is still large enough
+// for it.
+func (f *Fold) updateRail() {
+	rail := f.fold && f.railbox.Child() != nil && f.allocatedSize() >= f.rthres
+	if f.rail != rail {
+		f.rail = rail
+		f.railbox.SetVisible(rail)
+
+		if rail {
+			f.overlay.AddCSSClass("adaptive-sidebar-railed")
+		} else {
+			f.overlay.RemoveCSSClass("adaptive-sidebar-railed")
+		}
+	}
+
+	f.overlay.QueueAllocate()
+}
+
+// vertical returns true if the sidebar slides in from the top or the bottom.
+func (f *Fold) vertical() bool {
+	return f.fpos == gtk.PosTop || f.fpos == gtk.PosBottom
+}
+
+// orientation returns the orientation that the sidebar and the content are laid
+// out in.
+func (f *Fold) orientation() gtk.Orientation {
+	if f.vertical() {
+		return gtk.OrientationVertical
+	}
+	return gtk.OrientationHorizontal
+}
+
+// allocatedSize returns the fold's allocated size along its orientation.
+func (f *Fold) allocatedSize() int {
+	if f.vertical() {
+		return f.overlay.AllocatedHeight()
+	}
+	return f.overlay.AllocatedWidth()
+}
+
+// side returns the side that the sidebar is effectively on. Left and right
+// are mirrored if the fold's text direction is right-to-left.
+func (f *Fold) side() gtk.PositionType {
+	if f.vertical() || f.overlay.Direction() != gtk.TextDirRTL {
+		return f.fpos
+	}
+	if f.fpos == gtk.PosLeft {
+		return gtk.PosRight
+	}
+	return gtk.PosLeft
+}
+
+// placeSide puts the unfolded sidebar, the handle and the rail on the
+// sidebar's effective side of the content and picks the matching revealer
+// transition. The folded sidebar is positioned by childPosition instead.
+func (f *Fold) placeSide() {
+	docked := !f.fold
+	if docked && f.siderev.Parent() != nil {
+		f.main.Remove(f.siderev)
+	}
+	if f.handle.Parent() != nil {
+		f.main.Remove(f.handle)
+	}
+	if f.railbox.Parent() != nil {
+		f.main.Remove(f.railbox)
+	}
+
+	// first is true if the sidebar goes before the content in f.main.
+	var first bool
+	var transition gtk.RevealerTransitionType
+
+	if f.vertical() {
+		first = f.fpos == gtk.PosTop
+		if first {
+			transition = gtk.RevealerTransitionTypeSlideDown
+		} else {
+			transition = gtk.RevealerTransitionTypeSlideUp
+		}
+	} else {
+		left := f.side() == gtk.PosLeft
+
+		// GtkRevealer mirrors its slide transitions in right-to-left, so we
+		// have to mirror them back to get the physical direction.
+		slideRight := left != (f.siderev.Direction() == gtk.TextDirRTL)
+		if slideRight {
+			transition = gtk.RevealerTransitionTypeSlideRight
+		} else {
+			transition = gtk.RevealerTransitionTypeSlideLeft
+		}
+
+		// GtkBox also lays its children out from right to left in
+		// right-to-left.
+		first = left != (f.main.Direction() == gtk.TextDirRTL)
+	}
+
+	// The rail and then the handle always go between the sidebar and the
+	// content.
+	if first {
+		f.main.Prepend(f.railbox)
+		f.main.Prepend(f.handle)
+		if docked {
 			f.main.Prepend(f.siderev)
-		case gtk.PosRight:
+		}
+	} else {
+		f.main.Append(f.railbox)
+		f.main.Append(f.handle)
+		if docked {
 			f.main.Append(f.siderev)
 		}
+	}
 
-		f.doRevealSide()
-		f.notifyFolded()
+	if docked {
+		f.siderev.SetTransitionType(transition)
+	}
+}
+
+// dragDirection returns 1 if dragging towards positive X (or positive Y for
+// vertical folds) reveals the sidebar, or -1 otherwise.
+func (f *Fold) dragDirection() float64 {
+	switch f.side() {
+	case gtk.PosRight, gtk.PosBottom:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// sideSize returns the size of the folded sidebar along the fold's
+// orientation. It never exceeds the size of the fold itself.
+func (f *Fold) sideSize() int {
+	min, size, _, _ := f.sidebox.Measure(f.orientation(), -1)
+	if f.owidth != nil {
+		size = f.owidth(f.allocatedSize())
+		// Never go below what the sidebar needs.
+		if size < min {
+			size = min
+		}
+	}
+
+	if max := f.allocatedSize(); max > 0 && size > max {
+		size = max
 	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// restackSide puts the folded sidebar either above or below the content
+// depending on the fold transition. The reveal hint always stays right above
+// the content.
+func (f *Fold) restackSide() {
+	if !f.fold {
+		return
+	}
+	if f.ftrans == FoldTransitionUnder {
+		f.siderev.InsertBefore(f.overlay, f.main)
+	} else {
+		f.siderev.InsertAfter(f.overlay, f.main)
+	}
+	f.hint.InsertAfter(f.overlay, f.main)
+}
+
+// childPosition positions the folded sidebar and the rest of the fold inside
+// the overlay according to the sidebar's reveal progress.
+func (f *Fold) childPosition(widget gtk.Widgetter) (*gdk.Rectangle, bool) {
+	w := f.overlay.AllocatedWidth()
+	h := f.overlay.AllocatedHeight()
+
+	if !f.fold {
+		if glib.ObjectEq(widget, f.main) {
+			rect := gdk.NewRectangle(0, 0, w, h)
+			return &rect, true
+		}
+		rect := gdk.NewRectangle(0, 0, 0, 0)
+		return &rect, false
+	}
+
+	total := w
+	if f.vertical() {
+		total = h
+	}
+
+	size := f.sideSize()
+	shown := int(math.Round(float64(size) * f.progress))
+
+	// offset is how far the rail and the content in f.main are pushed away
+	// by the sidebar.
+	var offset int
+	if f.ftrans != FoldTransitionOver {
+		offset = shown
+	}
+
+	var rect gdk.Rectangle
+
+	switch {
+	case glib.ObjectEq(widget, f.siderev):
+		if f.ftrans == FoldTransitionUnder {
+			// The sidebar stays in place while the content uncovers it.
+			rect = f.stripRect(0, size, w, h)
+		} else {
+			rect = f.stripRect(shown-size, size, w, h)
+		}
+	case glib.ObjectEq(widget, f.main):
+		rect = f.stripRect(offset, total, w, h)
+	case glib.ObjectEq(widget, f.hint):
+		// The hint sticks to the edge of the sidebar as it comes out.
+		rect = f.stripRect(shown+f.railSize(), f.hsize, w, h)
+	default:
+		rect = gdk.NewRectangle(0, 0, 0, 0)
+		return &rect, false
+	}
+
+	return &rect, true
+}
+
+// stripRect returns the rectangle of a strip along the sidebar's side of the
+// fold. The strip is size pixels thick and starts pos pixels away from the
+// edge of that side. pos may be negative.
+func (f *Fold) stripRect(pos, size, w, h int) gdk.Rectangle {
+	switch f.side() {
+	case gtk.PosLeft:
+		return gdk.NewRectangle(pos, 0, size, h)
+	case gtk.PosRight:
+		return gdk.NewRectangle(w-pos-size, 0, size, h)
+	case gtk.PosTop:
+		return gdk.NewRectangle(0, pos, w, size)
+	default:
+		return gdk.NewRectangle(0, h-pos-size, w, size)
+	}
+}
+
+// railSize returns the size of the rail along the fold's orientation, or 0 if
+// the rail isn't shown.
+func (f *Fold) railSize() int {
+	if !f.rail {
+		return 0
+	}
+	_, size, _, _ := f.railbox.Measure(f.orientation(), -1)
+	return size
+}
+
+func (f *Fold) setProgress(progress float64) {
+	f.progress = progress
+	// Keep the sidebar mapped for as long as any part of it is visible or it's
+	// about to be.
+	f.siderev.SetRevealChild(progress > 0 || f.fold && f.revealed)
+	f.updateState()
+	f.overlay.QueueAllocate()
+}
+
+// animateProgress animates the folded sidebar's reveal progress towards the
+// given target.
+func (f *Fold) animateProgress(to float64) {
+	f.stopAnimation()
+
+	from := f.progress
+	if from == to || !f.overlay.Mapped() {
+		f.setProgress(to)
+		return
+	}
+
+	// Scale the duration down if the sidebar is already partially there, such
+	// as when the finger is lifted halfway.
+	duration := float64(FoldRevealDuration*1000) * math.Abs(to-from)
+	var start int64
+
+	f.animID = f.overlay.AddTickCallback(func(_ gtk.Widgetter, clock gdk.FrameClocker) bool {
+		now := gdk.BaseFrameClock(clock).FrameTime()
+		if start == 0 {
+			start = now
+		}
+
+		t := float64(now-start) / duration
+		if t >= 1 {
+			f.animID = 0
+			f.setProgress(to)
+			return false
+		}
+
+		f.setProgress(from + (to-from)*easeOutCubic(t))
+		return true
+	})
+}
+
+func (f *Fold) stopAnimation() {
+	if f.animID != 0 {
+		f.overlay.RemoveTickCallback(f.animID)
+		f.animID = 0
+	}
+}
+
+func easeOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
 }
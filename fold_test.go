@@ -6,6 +6,7 @@ import (
 
 	"github.com/diamondburned/adaptive"
 	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
 	"github.com/diamondburned/gotk4/pkg/gtk/v4"
 )
 
@@ -37,6 +38,42 @@ func ExampleFold() {
 	// Output:
 }
 
+func ExampleFold_revealAction() {
+	testapp.Run("fold-reveal-action", func(app *gtk.Application) {
+		adaptive.Init()
+
+		stack := newStack()
+		stack.SetHExpand(true)
+
+		stackside := gtk.NewStackSidebar()
+		stackside.SetStack(stack)
+
+		fold := adaptive.NewFold(gtk.PosLeft)
+		fold.SetSideChild(stackside)
+		fold.SetChild(stack)
+
+		// The menu button and F9 toggle the sidebar through its action.
+		menu := gio.NewMenu()
+		menu.Append("Show Sidebar", "fold."+adaptive.FoldRevealAction)
+
+		menuButton := gtk.NewMenuButton()
+		menuButton.SetIconName("open-menu-symbolic")
+		menuButton.SetMenuModel(menu)
+
+		h := gtk.NewHeaderBar()
+		h.PackEnd(menuButton)
+
+		w := testapp.NewWindow(app, "Example Sidebar Action", 450, 300)
+		w.SetChild(fold)
+		w.SetTitlebar(h)
+		w.InsertActionGroup("fold", fold.ActionGroup())
+		w.Show()
+
+		app.SetAccelsForAction("fold."+adaptive.FoldRevealAction, []string{"F9"})
+	})
+	// Output:
+}
+
 func newStack() *gtk.Stack {
 	stack := gtk.NewStack()
 	stack.SetTransitionType(gtk.StackTransitionTypeSlideUpDown)
@@ -0,0 +1,439 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// LeafletNavigation is the direction to navigate a folded Leaflet in.
+type LeafletNavigation int
+
+const (
+	// LeafletBack navigates to the previous navigatable child.
+	LeafletBack LeafletNavigation = iota
+	// LeafletForward navigates to the next navigatable child.
+	LeafletForward
+)
+
+// LeafletPage is a child in a Leaflet.
+type LeafletPage struct {
+	child       gtk.Widgetter
+	name        string
+	navigatable bool
+}
+
+// Child returns the page's child widget.
+func (p *LeafletPage) Child() gtk.Widgetter {
+	return p.child
+}
+
+// Name returns the page's name.
+func (p *LeafletPage) Name() string {
+	return p.name
+}
+
+// SetName sets the page's name, which can be used with
+// Leaflet.SetVisibleChildName.
+func (p *LeafletPage) SetName(name string) {
+	p.name = name
+}
+
+// SetNavigatable sets whether or not the page can be navigated to using
+// Leaflet.Navigate and swipes while the leaflet is folded. Pages such as
+// separators usually shouldn't be. Pages are navigatable by default.
+func (p *LeafletPage) SetNavigatable(navigatable bool) {
+	p.navigatable = navigatable
+}
+
+// IsNavigatable returns true if the page can be navigated to.
+func (p *LeafletPage) IsNavigatable() bool {
+	return p.navigatable
+}
+
+// Leaflet is a component that acts similar to libadwaita's AdwLeaflet. It
+// shows all its children side by side when it's wide enough and folds into a
+// stack that shows one child at a time when it's not. While folded, the user
+// can navigate back and forward between the children by swiping.
+type Leaflet struct {
+	*gtk.Widget
+	overlay *gtk.Overlay
+	scroll  *gtk.ScrolledWindow
+	box     *gtk.Box
+	stack   *gtk.Stack
+
+	pages   []*LeafletPage
+	visible *LeafletPage
+
+	onFold    callbacks
+	onVisible callbacks
+
+	fpolicy FoldPolicy
+	fthres  int
+	fold    bool
+
+	canBack    bool
+	canForward bool
+}
+
+// NewLeaflet creates a new empty leaflet.
+func NewLeaflet() *Leaflet {
+	l := &Leaflet{
+		canBack:    true,
+		canForward: true,
+	}
+
+	l.box = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	l.box.SetHExpand(true)
+	l.box.SetVExpand(true)
+
+	// The box is kept in a scrolled window without scrollbars, so that the
+	// leaflet can be made narrower than its children and fold once it is.
+	l.scroll = gtk.NewScrolledWindow()
+	l.scroll.SetPolicy(gtk.PolicyExternal, gtk.PolicyNever)
+	l.scroll.SetPropagateNaturalWidth(true)
+	l.scroll.SetPropagateNaturalHeight(true)
+	l.scroll.SetChild(l.box)
+
+	l.stack = gtk.NewStack()
+	l.stack.SetHExpand(true)
+	l.stack.SetVExpand(true)
+	l.stack.SetTransitionDuration(FoldRevealDuration)
+
+	l.overlay = gtk.NewOverlay()
+	l.overlay.AddCSSClass("adaptive-leaflet")
+	l.overlay.SetChild(l.scroll)
+	l.overlay.SetOverflow(gtk.OverflowHidden)
+
+	l.Widget = gtk.BaseWidget(l.overlay)
+	watchSize(l.overlay, func(int, int) { l.updateLayout() })
+
+	swiper := gtk.NewGestureSwipe()
+	swiper.SetTouchOnly(true)
+	swiper.ConnectBegin(func(*gdk.EventSequence) {
+		if !l.fold {
+			swiper.SetState(gtk.EventSequenceDenied)
+		}
+	})
+	swiper.ConnectSwipe(func(velX, velY float64) {
+		if !isInThreshold(velX, FoldXThreshold) || !isInThreshold(velY, FoldYThreshold) {
+			return
+		}
+
+		// Swiping towards the end of the text direction brings the previous
+		// child back in.
+		towardsEnd := velX > 0
+		if l.overlay.Direction() == gtk.TextDirRTL {
+			towardsEnd = !towardsEnd
+		}
+
+		if towardsEnd {
+			if l.canBack {
+				l.Navigate(LeafletBack)
+			}
+		} else {
+			if l.canForward {
+				l.Navigate(LeafletForward)
+			}
+		}
+	})
+	l.overlay.AddController(swiper)
+
+	return l
+}
+
+// Append appends the given child to the end of the leaflet. If the leaflet has
+// no visible child yet, then it becomes the visible child.
+func (l *Leaflet) Append(child gtk.Widgetter) *LeafletPage {
+	page := &LeafletPage{
+		child:       child,
+		navigatable: true,
+	}
+	l.pages = append(l.pages, page)
+
+	if l.fold {
+		l.stack.AddChild(child)
+	} else {
+		l.box.Append(child)
+	}
+
+	if l.visible == nil {
+		l.setVisible(page, LeafletForward)
+	}
+
+	l.updateLayout()
+	return page
+}
+
+// Remove removes the given child from the leaflet. If it was the visible
+// child, then the nearest navigatable child becomes visible.
+func (l *Leaflet) Remove(child gtk.Widgetter) {
+	i := l.pageIndex(child)
+	if i == -1 {
+		return
+	}
+
+	page := l.pages[i]
+
+	if page == l.visible {
+		next := l.adjacent(LeafletBack)
+		if next == nil {
+			next = l.adjacent(LeafletForward)
+		}
+		l.setVisible(next, LeafletBack)
+	}
+
+	l.pages = append(l.pages[:i], l.pages[i+1:]...)
+
+	if l.fold {
+		l.stack.Remove(child)
+	} else {
+		l.box.Remove(child)
+	}
+
+	l.updateLayout()
+}
+
+// Page returns the page of the given child, or nil if the child is not in the
+// leaflet.
+func (l *Leaflet) Page(child gtk.Widgetter) *LeafletPage {
+	if i := l.pageIndex(child); i != -1 {
+		return l.pages[i]
+	}
+	return nil
+}
+
+func (l *Leaflet) pageIndex(child gtk.Widgetter) int {
+	for i, page := range l.pages {
+		if glib.ObjectEq(page.child, child) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetVisibleChild sets the child that is shown while the leaflet is folded.
+func (l *Leaflet) SetVisibleChild(child gtk.Widgetter) {
+	page := l.Page(child)
+	if page == nil || page == l.visible {
+		return
+	}
+
+	// Without a visible child yet, treat it as going forward.
+	dir := LeafletForward
+	if l.visible != nil && l.pageIndex(child) < l.pageIndex(l.visible.child) {
+		dir = LeafletBack
+	}
+
+	l.setVisible(page, dir)
+}
+
+// VisibleChild returns the child that is shown while the leaflet is folded, or
+// nil if the leaflet is empty.
+func (l *Leaflet) VisibleChild() gtk.Widgetter {
+	if l.visible == nil {
+		return nil
+	}
+	return l.visible.child
+}
+
+// SetVisibleChildName sets the visible child by its page name.
+func (l *Leaflet) SetVisibleChildName(name string) {
+	for _, page := range l.pages {
+		if page.name == name {
+			l.SetVisibleChild(page.child)
+			return
+		}
+	}
+}
+
+// VisibleChildName returns the page name of the visible child.
+func (l *Leaflet) VisibleChildName() string {
+	if l.visible == nil {
+		return ""
+	}
+	return l.visible.name
+}
+
+// NotifyVisibleChild subscribes fn to be called when the visible child
+// changes. The returned callback unsubscribes fn.
+func (l *Leaflet) NotifyVisibleChild(fn func(child gtk.Widgetter)) func() {
+	return l.onVisible.add(func() { fn(l.VisibleChild()) })
+}
+
+// Navigate makes the previous or the next navigatable child visible. It
+// returns false if there is no such child.
+func (l *Leaflet) Navigate(dir LeafletNavigation) bool {
+	page := l.adjacent(dir)
+	if page == nil {
+		return false
+	}
+	l.setVisible(page, dir)
+	return true
+}
+
+// AdjacentChild returns the previous or the next navigatable child, or nil if
+// there is none.
+func (l *Leaflet) AdjacentChild(dir LeafletNavigation) gtk.Widgetter {
+	if page := l.adjacent(dir); page != nil {
+		return page.child
+	}
+	return nil
+}
+
+func (l *Leaflet) adjacent(dir LeafletNavigation) *LeafletPage {
+	if l.visible == nil {
+		return nil
+	}
+
+	step := 1
+	if dir == LeafletBack {
+		step = -1
+	}
+
+	for i := l.pageIndex(l.visible.child) + step; i >= 0 && i < len(l.pages); i += step {
+		if l.pages[i].navigatable {
+			return l.pages[i]
+		}
+	}
+
+	return nil
+}
+
+// SetCanNavigateBack sets whether or not swiping towards the end of the text
+// direction navigates back while folded. It is true by default.
+func (l *Leaflet) SetCanNavigateBack(canNavigate bool) {
+	l.canBack = canNavigate
+}
+
+// SetCanNavigateForward sets whether or not swiping towards the start of the
+// text direction navigates forward while folded. It is true by default.
+func (l *Leaflet) SetCanNavigateForward(canNavigate bool) {
+	l.canForward = canNavigate
+}
+
+// SetFoldThreshold sets the width below which the leaflet folds. If threshold
+// is 0 or less, which is the default, then the leaflet folds once its children
+// no longer fit side by side at their minimum widths.
+func (l *Leaflet) SetFoldThreshold(threshold int) {
+	l.fthres = threshold
+	l.updateLayout()
+}
+
+// FoldThreshold returns the fold threshold.
+func (l *Leaflet) FoldThreshold() int {
+	return l.fthres
+}
+
+// SetFoldPolicy sets the policy that determines when the leaflet is folded.
+func (l *Leaflet) SetFoldPolicy(policy FoldPolicy) {
+	l.fpolicy = policy
+	l.updateLayout()
+}
+
+// FoldPolicy returns the leaflet's current fold policy.
+func (l *Leaflet) FoldPolicy() FoldPolicy {
+	return l.fpolicy
+}
+
+// IsFolded returns true if the leaflet is currently folded.
+func (l *Leaflet) IsFolded() bool {
+	return l.fold
+}
+
+// NotifyFolded subscribes fn to be called if the leaflet is folded or
+// unfolded. fn is also called once with the current fold state. The returned
+// callback unsubscribes fn.
+func (l *Leaflet) NotifyFolded(fn func(folded bool)) func() {
+	remove := l.onFold.add(func() { fn(l.fold) })
+	fn(l.fold)
+	return remove
+}
+
+// threshold returns the width below which the leaflet should fold.
+func (l *Leaflet) threshold() int {
+	if l.fthres > 0 {
+		return l.fthres
+	}
+
+	var min int
+	for _, page := range l.pages {
+		child := gtk.BaseWidget(page.child)
+		if !child.Visible() {
+			continue
+		}
+		w, _, _, _ := child.Measure(gtk.OrientationHorizontal, -1)
+		min += w
+	}
+	return min
+}
+
+func (l *Leaflet) updateLayout() {
+	switch l.fpolicy {
+	case FoldAlways:
+		l.setFold(true)
+	case FoldNever:
+		l.setFold(false)
+	default:
+		width := l.overlay.AllocatedWidth()
+		if width == 0 {
+			// Not allocated yet.
+			return
+		}
+		l.setFold(width < l.threshold())
+	}
+}
+
+func (l *Leaflet) setFold(fold bool) {
+	if l.fold == fold {
+		return
+	}
+	l.fold = fold
+
+	// Move the children over to whichever container is now shown.
+	if fold {
+		for _, page := range l.pages {
+			l.box.Remove(page.child)
+			l.stack.AddChild(page.child)
+		}
+		if l.visible != nil {
+			l.stack.SetTransitionType(gtk.StackTransitionTypeNone)
+			l.stack.SetVisibleChild(l.visible.child)
+		}
+		l.overlay.SetChild(l.stack)
+		l.overlay.AddCSSClass("adaptive-leaflet-folded")
+	} else {
+		for _, page := range l.pages {
+			l.stack.Remove(page.child)
+			l.box.Append(page.child)
+		}
+		l.overlay.SetChild(l.scroll)
+		l.overlay.RemoveCSSClass("adaptive-leaflet-folded")
+	}
+
+	l.onFold.call()
+}
+
+func (l *Leaflet) setVisible(page *LeafletPage, dir LeafletNavigation) {
+	if page == l.visible {
+		return
+	}
+	l.visible = page
+
+	if page != nil && l.fold {
+		l.stack.SetTransitionType(slideTransition(dir == LeafletForward))
+		l.stack.SetVisibleChild(page.child)
+	}
+
+	l.onVisible.call()
+}
+
+// slideTransition returns the stack transition that slides the new child in
+// from the end when going forward and from the start when going back. The stack
+// already mirrors the slides for right-to-left text directions.
+func slideTransition(forward bool) gtk.StackTransitionType {
+	if forward {
+		return gtk.StackTransitionTypeSlideLeft
+	}
+	return gtk.StackTransitionTypeSlideRight
+}
@@ -0,0 +1,43 @@
+package adaptive_test
+
+import (
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleLeaflet() {
+	testapp.Run("leaflet", func(app *gtk.Application) {
+		adaptive.Init()
+
+		leaflet := adaptive.NewLeaflet()
+		leaflet.SetFoldThreshold(600)
+
+		for _, name := range []string{"Folders", "Messages", "Message"} {
+			label := gtk.NewLabel(name)
+			label.SetHExpand(true)
+			label.SetSizeRequest(150, -1)
+
+			page := leaflet.Append(label)
+			page.SetName(name)
+
+			if name != "Message" {
+				separator := gtk.NewSeparator(gtk.OrientationVertical)
+				leaflet.Append(separator).SetNavigatable(false)
+			}
+		}
+
+		back := gtk.NewButtonFromIconName("go-previous-symbolic")
+		back.ConnectClicked(func() { leaflet.Navigate(adaptive.LeafletBack) })
+		leaflet.NotifyFolded(func(folded bool) { back.SetVisible(folded) })
+
+		h := gtk.NewHeaderBar()
+		h.PackStart(back)
+
+		w := testapp.NewWindow(app, "Example Leaflet", 700, 300)
+		w.SetChild(leaflet)
+		w.SetTitlebar(h)
+		w.Show()
+	})
+	// Output:
+}
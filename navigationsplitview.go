@@ -0,0 +1,294 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// SplitBackButtonIcon is the default icon name for a split view back button.
+const SplitBackButtonIcon = "go-previous-symbolic"
+
+// SplitBackButton is a button that goes back from the content to the sidebar
+// of a collapsed NavigationSplitView. It is only shown while the split view is
+// collapsed and showing its content.
+type SplitBackButton struct {
+	*gtk.Revealer
+	Button *gtk.Button
+}
+
+// NewSplitBackButton creates a new split view back button. The button is hidden
+// by default until a split view is connected to it.
+func NewSplitBackButton() *SplitBackButton {
+	button := gtk.NewButtonFromIconName(SplitBackButtonIcon)
+
+	revealer := gtk.NewRevealer()
+	revealer.AddCSSClass("adaptive-splitview-back-button")
+	revealer.SetTransitionType(gtk.RevealerTransitionTypeCrossfade)
+	revealer.SetChild(button)
+	revealer.SetRevealChild(false)
+
+	return &SplitBackButton{
+		Revealer: revealer,
+		Button:   button,
+	}
+}
+
+// ConnectSplitView connects the back button to the given split view. The
+// returned callback disconnects the button from the split view.
+func (b *SplitBackButton) ConnectSplitView(view *NavigationSplitView) func() {
+	clicked := b.Button.ConnectClicked(func() {
+		view.SetShowContent(false)
+	})
+
+	update := func() {
+		b.SetRevealChild(view.IsCollapsed() && view.ShowContent())
+	}
+
+	uncollapsed := view.NotifyCollapsed(func(bool) { update() })
+	unshown := view.NotifyShowContent(func(bool) { update() })
+
+	return func() {
+		b.Button.HandlerDisconnect(clicked)
+		uncollapsed()
+		unshown()
+	}
+}
+
+// NavigationSplitView is a component that acts similar to libadwaita's
+// AdwNavigationSplitView. It shows a sidebar, such as a list, and its content
+// side by side. Once the collapse condition matches, it turns into a
+// NavigationView with the sidebar as the first page and the content pushed
+// over it when it is shown.
+type NavigationSplitView struct {
+	*gtk.Widget
+	overlay *gtk.Overlay
+	box     *gtk.Box
+	nav     *NavigationView
+
+	sidebin    *Bin
+	contentbin *Bin
+	// sidepagebin and pagebin are the children of the navigation pages, which
+	// hold sidebin and contentbin while collapsed.
+	sidepagebin *Bin
+	pagebin     *Bin
+	sidepage    *NavigationPage
+	page        *NavigationPage
+
+	onCollapse callbacks
+	onContent  callbacks
+
+	cthres    int
+	ccond     BreakpointCondition
+	collapsed bool
+	content   bool
+	// moving is true while the bins are being moved around, during which the
+	// navigation view's changes are ours.
+	moving bool
+}
+
+// NewNavigationSplitView creates a new split view.
+func NewNavigationSplitView() *NavigationSplitView {
+	v := &NavigationSplitView{
+		cthres: defaultFoldThreshold,
+	}
+
+	v.sidebin = NewBin()
+	v.sidebin.AddCSSClass("adaptive-splitview-sidebar")
+	v.sidebin.SetVExpand(true)
+
+	v.contentbin = NewBin()
+	v.contentbin.AddCSSClass("adaptive-splitview-content")
+	v.contentbin.SetHExpand(true)
+	v.contentbin.SetVExpand(true)
+
+	v.sidepagebin = NewBin()
+	v.pagebin = NewBin()
+	v.sidepage = NewNavigationPage(v.sidepagebin, "", "sidebar")
+	v.page = NewNavigationPage(v.pagebin, "", "content")
+
+	v.box = gtk.NewBox(gtk.OrientationHorizontal, 0)
+	v.box.Append(v.sidebin)
+	v.box.Append(gtk.NewSeparator(gtk.OrientationVertical))
+	v.box.Append(v.contentbin)
+
+	v.nav = NewNavigationView()
+	v.nav.Add(v.page)
+	v.nav.NotifyVisiblePage(func(page *NavigationPage) {
+		if v.collapsed && !v.moving && page != nil {
+			// The user may have gone back without us.
+			v.setShowContent(page == v.page)
+		}
+	})
+
+	v.overlay = gtk.NewOverlay()
+	v.overlay.AddCSSClass("adaptive-splitview")
+	v.overlay.SetChild(v.box)
+
+	v.Widget = gtk.BaseWidget(v.overlay)
+	watchSize(v.overlay, func(int, int) { v.updateLayout() })
+
+	return v
+}
+
+// SetSidebar sets the sidebar's child and title. The title is shown as the
+// page's title while collapsed.
+func (v *NavigationSplitView) SetSidebar(child gtk.Widgetter, title string) {
+	v.sidebin.SetChild(child)
+	v.sidepage.SetTitle(title)
+}
+
+// SetContent sets the content's child and title. The title is shown as the
+// page's title while collapsed.
+func (v *NavigationSplitView) SetContent(child gtk.Widgetter, title string) {
+	v.contentbin.SetChild(child)
+	v.page.SetTitle(title)
+}
+
+// SidebarPage returns the navigation page of the sidebar, which is used while
+// collapsed.
+func (v *NavigationSplitView) SidebarPage() *NavigationPage {
+	return v.sidepage
+}
+
+// ContentPage returns the navigation page of the content, which is used while
+// collapsed.
+func (v *NavigationSplitView) ContentPage() *NavigationPage {
+	return v.page
+}
+
+// NavigationView returns the navigation view that is shown while collapsed.
+// Its ActionGroup can be inserted into the window so that the
+// NavigationPopAction works from the header bar.
+func (v *NavigationSplitView) NavigationView() *NavigationView {
+	return v.nav
+}
+
+// SetShowContent sets whether or not the content is shown instead of the
+// sidebar while collapsed. This is usually called when an item in the sidebar
+// is selected. While not collapsed, both are always shown, but the value is
+// kept for when the split view collapses.
+func (v *NavigationSplitView) SetShowContent(show bool) {
+	v.setShowContent(show)
+	if !v.collapsed {
+		return
+	}
+
+	if show {
+		v.nav.PushByTag(v.page.Tag())
+	} else {
+		v.nav.PopToTag(v.sidepage.Tag())
+	}
+}
+
+func (v *NavigationSplitView) setShowContent(show bool) {
+	if v.content == show {
+		return
+	}
+	v.content = show
+	v.onContent.call()
+}
+
+// ShowContent returns true if the content is shown instead of the sidebar
+// while collapsed.
+func (v *NavigationSplitView) ShowContent() bool {
+	return v.content
+}
+
+// NotifyShowContent subscribes fn to be called when ShowContent changes. fn is
+// also called once with the current value. The returned callback unsubscribes
+// fn.
+func (v *NavigationSplitView) NotifyShowContent(fn func(show bool)) func() {
+	remove := v.onContent.add(func() { fn(v.content) })
+	fn(v.content)
+	return remove
+}
+
+// SetCollapseThreshold sets the width below which the split view collapses.
+func (v *NavigationSplitView) SetCollapseThreshold(threshold int) {
+	v.cthres = threshold
+	v.updateLayout()
+}
+
+// CollapseThreshold returns the collapse threshold.
+func (v *NavigationSplitView) CollapseThreshold() int {
+	return v.cthres
+}
+
+// SetCollapseCondition sets the condition on the split view's size that
+// determines whether or not it should be collapsed. It overrides
+// SetCollapseThreshold. If cond is nil, then the threshold is used again.
+func (v *NavigationSplitView) SetCollapseCondition(cond BreakpointCondition) {
+	v.ccond = cond
+	v.updateLayout()
+}
+
+// CollapseCondition returns the condition that determines whether or not the
+// split view should be collapsed.
+func (v *NavigationSplitView) CollapseCondition() BreakpointCondition {
+	if v.ccond != nil {
+		return v.ccond
+	}
+	return BreakpointMaxWidth(v.cthres - 1)
+}
+
+// IsCollapsed returns true if the split view is currently collapsed.
+func (v *NavigationSplitView) IsCollapsed() bool {
+	return v.collapsed
+}
+
+// NotifyCollapsed subscribes fn to be called if the split view collapses or
+// expands. fn is also called once with the current state. The returned
+// callback unsubscribes fn.
+func (v *NavigationSplitView) NotifyCollapsed(fn func(collapsed bool)) func() {
+	remove := v.onCollapse.add(func() { fn(v.collapsed) })
+	fn(v.collapsed)
+	return remove
+}
+
+func (v *NavigationSplitView) updateLayout() {
+	w := v.overlay.AllocatedWidth()
+	h := v.overlay.AllocatedHeight()
+	if w == 0 && h == 0 {
+		// Not allocated yet.
+		return
+	}
+	v.setCollapsed(v.CollapseCondition().Matches(w, h))
+}
+
+func (v *NavigationSplitView) setCollapsed(collapsed bool) {
+	if v.collapsed == collapsed {
+		return
+	}
+	v.collapsed = collapsed
+
+	v.moving = true
+	defer func() { v.moving = false }()
+
+	if collapsed {
+		v.box.Remove(v.sidebin)
+		v.box.Remove(v.contentbin)
+		v.sidepagebin.SetChild(v.sidebin)
+		v.pagebin.SetChild(v.contentbin)
+
+		v.nav.Push(v.sidepage)
+		if v.content {
+			v.nav.Push(v.page)
+		}
+
+		v.overlay.SetChild(v.nav)
+		v.overlay.AddCSSClass("adaptive-splitview-collapsed")
+	} else {
+		// Only keep the sidebar page in the navigation stack, so that it's
+		// ready for the next time we collapse.
+		v.nav.PopToTag(v.sidepage.Tag())
+
+		v.sidepagebin.SetChild(nil)
+		v.pagebin.SetChild(nil)
+		v.box.Prepend(v.sidebin)
+		v.box.Append(v.contentbin)
+
+		v.overlay.SetChild(v.box)
+		v.overlay.RemoveCSSClass("adaptive-splitview-collapsed")
+	}
+
+	v.onCollapse.call()
+}
@@ -0,0 +1,45 @@
+package adaptive_test
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleNavigationSplitView() {
+	testapp.Run("navigation-split-view", func(app *gtk.Application) {
+		adaptive.Init()
+
+		detail := gtk.NewLabel("Nothing selected.")
+
+		list := gtk.NewListBox()
+		list.SetSizeRequest(200, -1)
+		for i := 0; i < 5; i++ {
+			list.Append(gtk.NewLabel(fmt.Sprintf("Contact %d", i)))
+		}
+
+		view := adaptive.NewNavigationSplitView()
+		view.SetCollapseThreshold(500)
+		view.SetSidebar(list, "Contacts")
+		view.SetContent(detail, "Contact")
+
+		list.ConnectRowActivated(func(row *gtk.ListBoxRow) {
+			detail.SetText(fmt.Sprintf("You picked contact %d.", row.Index()))
+			view.SetShowContent(true)
+		})
+
+		back := adaptive.NewSplitBackButton()
+		back.ConnectSplitView(view)
+
+		h := gtk.NewHeaderBar()
+		h.PackStart(back)
+
+		w := testapp.NewWindow(app, "Example Split View", 600, 300)
+		w.SetChild(view)
+		w.SetTitlebar(h)
+		w.Show()
+	})
+	// Output:
+}
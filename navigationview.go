@@ -0,0 +1,399 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gdk/v4"
+	"github.com/diamondburned/gotk4/pkg/gio/v2"
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// NavigationPopAction is the name of the action that pops the visible page off
+// a NavigationView. The action is inserted into the view with the
+// "navigation" prefix, so its full name is "navigation.pop". It is bound to
+// Alt+Left and the Back key within the view.
+const NavigationPopAction = "pop"
+
+// NavigationSwipeEdge is the size in pixels of the region along the start edge
+// of a NavigationView where a swipe can start to go back.
+const NavigationSwipeEdge = 24
+
+// navigationBackButton is the mouse button that is usually the back button.
+const navigationBackButton = 8
+
+// NavigationPage is a page in a NavigationView.
+type NavigationPage struct {
+	child  gtk.Widgetter
+	title  string
+	tag    string
+	canPop bool
+
+	view     *NavigationView
+	onShown  callbacks
+	onHidden callbacks
+}
+
+// NewNavigationPage creates a new page with the given child, title and tag.
+// The tag can be used to push the page by name once the page is added into a
+// NavigationView, and it may be empty.
+func NewNavigationPage(child gtk.Widgetter, title, tag string) *NavigationPage {
+	return &NavigationPage{
+		child:  child,
+		title:  title,
+		tag:    tag,
+		canPop: true,
+	}
+}
+
+// Child returns the page's child widget.
+func (p *NavigationPage) Child() gtk.Widgetter {
+	return p.child
+}
+
+// Tag returns the page's tag.
+func (p *NavigationPage) Tag() string {
+	return p.tag
+}
+
+// Title returns the page's title.
+func (p *NavigationPage) Title() string {
+	return p.title
+}
+
+// SetTitle sets the page's title, which headers can show for the visible page.
+func (p *NavigationPage) SetTitle(title string) {
+	p.title = title
+	if p.view != nil && p.view.VisiblePage() == p {
+		p.view.onVisible.call()
+	}
+}
+
+// SetCanPop sets whether or not the user can pop the page off the view using
+// the back action or gestures. Pages can be popped by default. Pop and PopToTag
+// can still pop the page.
+func (p *NavigationPage) SetCanPop(canPop bool) {
+	p.canPop = canPop
+	if p.view != nil {
+		p.view.updateBack()
+	}
+}
+
+// CanPop returns true if the user can pop the page off the view.
+func (p *NavigationPage) CanPop() bool {
+	return p.canPop
+}
+
+// NotifyShown subscribes fn to be called when the page becomes the visible
+// page of its view. This is a good place to start loading the page's content,
+// such as into a LoadablePage. The returned callback unsubscribes fn.
+func (p *NavigationPage) NotifyShown(fn func()) func() {
+	return p.onShown.add(fn)
+}
+
+// NotifyHidden subscribes fn to be called when the page stops being the
+// visible page of its view, either because another page was pushed over it or
+// because it was popped. This is a good place to cancel the context of a
+// LoadablePage. The returned callback unsubscribes fn.
+func (p *NavigationPage) NotifyHidden(fn func()) func() {
+	return p.onHidden.add(fn)
+}
+
+// NavigationView is a component that acts similar to libadwaita's
+// AdwNavigationView. It holds a stack of pages that are pushed and popped,
+// sliding in the direction of the navigation. The visible page can be popped
+// using the NavigationPopAction, the mouse's back button or by swiping from the
+// start edge.
+type NavigationView struct {
+	*gtk.Widget
+	overlay *gtk.Overlay
+	stack   *gtk.Stack
+
+	actions   *gio.SimpleActionGroup
+	popAction *gio.SimpleAction
+
+	// pages are all the pages in the view. added are the pages that were
+	// added using Add, which stay in the view even when they're not in the
+	// navigation stack.
+	pages   []*NavigationPage
+	added   []*NavigationPage
+	history []*NavigationPage
+
+	onVisible callbacks
+}
+
+// NewNavigationView creates a new empty navigation view.
+func NewNavigationView() *NavigationView {
+	v := &NavigationView{}
+
+	v.stack = gtk.NewStack()
+	v.stack.SetHExpand(true)
+	v.stack.SetVExpand(true)
+	v.stack.SetTransitionDuration(FoldRevealDuration)
+	v.stack.Connect("notify::transition-running", v.removeHidden)
+
+	v.overlay = gtk.NewOverlay()
+	v.overlay.AddCSSClass("adaptive-navigationview")
+	v.overlay.SetChild(v.stack)
+
+	v.popAction = gio.NewSimpleAction(NavigationPopAction, nil)
+	v.popAction.ConnectActivate(func(*glib.Variant) { v.userPop() })
+	v.popAction.SetEnabled(false)
+
+	v.actions = gio.NewSimpleActionGroup()
+	v.actions.AddAction(v.popAction)
+	v.overlay.InsertActionGroup("navigation", v.actions)
+
+	shortcuts := gtk.NewShortcutController()
+	shortcuts.AddShortcut(gtk.NewShortcut(
+		gtk.NewShortcutTriggerParseString("<Alt>Left|Back"),
+		gtk.NewNamedAction("navigation."+NavigationPopAction),
+	))
+	v.overlay.AddController(shortcuts)
+
+	backClicker := gtk.NewGestureClick()
+	backClicker.SetButton(navigationBackButton)
+	backClicker.ConnectPressed(func(n int, x, y float64) {
+		if v.userPop() {
+			backClicker.SetState(gtk.EventSequenceClaimed)
+		}
+	})
+	v.overlay.AddController(backClicker)
+
+	swiper := gtk.NewGestureSwipe()
+	swiper.SetTouchOnly(true)
+	swiper.ConnectBegin(func(sequence *gdk.EventSequence) {
+		x, _, _ := swiper.Point(sequence)
+		if v.overlay.Direction() == gtk.TextDirRTL {
+			x = float64(v.overlay.AllocatedWidth()) - x
+		}
+		if !v.popAction.Enabled() || x > NavigationSwipeEdge {
+			swiper.SetState(gtk.EventSequenceDenied)
+		}
+	})
+	swiper.ConnectSwipe(func(velX, velY float64) {
+		if v.overlay.Direction() == gtk.TextDirRTL {
+			velX = -velX
+		}
+		// Only swipes towards the end go back.
+		if velX > 0 && isInThreshold(velX, FoldXThreshold) && isInThreshold(velY, FoldYThreshold) {
+			v.userPop()
+		}
+	})
+	v.overlay.AddController(swiper)
+
+	v.Widget = gtk.BaseWidget(v.overlay)
+	return v
+}
+
+// ActionGroup returns the view's action group, which contains the
+// NavigationPopAction. It is already inserted into the view as "navigation".
+func (v *NavigationView) ActionGroup() *gio.SimpleActionGroup {
+	return v.actions
+}
+
+// Add adds the given page into the view without pushing it, so that it can be
+// pushed later using PushByTag.
+func (v *NavigationView) Add(page *NavigationPage) {
+	if v.isAdded(page) {
+		return
+	}
+	v.added = append(v.added, page)
+	v.addPage(page)
+}
+
+// Remove removes the given page that was added using Add. If the page is in the
+// navigation stack, then it is removed once it is popped.
+func (v *NavigationView) Remove(page *NavigationPage) {
+	for i, added := range v.added {
+		if added == page {
+			v.added = append(v.added[:i], v.added[i+1:]...)
+			break
+		}
+	}
+	v.removeHidden()
+}
+
+// FindPage returns the page with the given tag in either the navigation stack
+// or the added pages, or nil if there is none.
+func (v *NavigationView) FindPage(tag string) *NavigationPage {
+	for i := len(v.history) - 1; i >= 0; i-- {
+		if v.history[i].tag == tag {
+			return v.history[i]
+		}
+	}
+	for _, page := range v.added {
+		if page.tag == tag {
+			return page
+		}
+	}
+	return nil
+}
+
+// Push pushes the given page onto the navigation stack, making it the visible
+// page. It does nothing if the page is already in the navigation stack.
+func (v *NavigationView) Push(page *NavigationPage) {
+	for _, pushed := range v.history {
+		if pushed == page {
+			return
+		}
+	}
+
+	v.addPage(page)
+	v.navigate(append(v.history, page), true)
+}
+
+// PushByTag pushes the page with the given tag, which must have been added
+// using Add. It returns false if there is no such page.
+func (v *NavigationView) PushByTag(tag string) bool {
+	for _, page := range v.added {
+		if page.tag == tag {
+			v.Push(page)
+			return true
+		}
+	}
+	return false
+}
+
+// Pop pops the visible page off the navigation stack. It returns false if
+// there is no page to go back to.
+func (v *NavigationView) Pop() bool {
+	if len(v.history) < 2 {
+		return false
+	}
+	v.navigate(v.history[:len(v.history)-1], false)
+	return true
+}
+
+// PopToTag pops pages off the navigation stack until the page with the given
+// tag is visible. It returns false if there is no such page in the navigation
+// stack.
+func (v *NavigationView) PopToTag(tag string) bool {
+	for i := len(v.history) - 1; i >= 0; i-- {
+		if v.history[i].tag == tag {
+			if i == len(v.history)-1 {
+				return true
+			}
+			v.navigate(v.history[:i+1], false)
+			return true
+		}
+	}
+	return false
+}
+
+// VisiblePage returns the page on top of the navigation stack, or nil if the
+// stack is empty.
+func (v *NavigationView) VisiblePage() *NavigationPage {
+	if len(v.history) == 0 {
+		return nil
+	}
+	return v.history[len(v.history)-1]
+}
+
+// PreviousPage returns the page that Pop goes back to, or nil if there is
+// none.
+func (v *NavigationView) PreviousPage() *NavigationPage {
+	if len(v.history) < 2 {
+		return nil
+	}
+	return v.history[len(v.history)-2]
+}
+
+// NavigationStack returns the pages in the navigation stack, from the bottom
+// to the visible page.
+func (v *NavigationView) NavigationStack() []*NavigationPage {
+	return append([]*NavigationPage(nil), v.history...)
+}
+
+// NotifyVisiblePage subscribes fn to be called when the visible page or its
+// title changes. fn is also called once with the current visible page, which
+// may be nil. The returned callback unsubscribes fn.
+func (v *NavigationView) NotifyVisiblePage(fn func(page *NavigationPage)) func() {
+	remove := v.onVisible.add(func() { fn(v.VisiblePage()) })
+	fn(v.VisiblePage())
+	return remove
+}
+
+// userPop pops the visible page if the user is allowed to.
+func (v *NavigationView) userPop() bool {
+	if !v.popAction.Enabled() {
+		return false
+	}
+	return v.Pop()
+}
+
+func (v *NavigationView) navigate(history []*NavigationPage, forward bool) {
+	old := v.VisiblePage()
+	v.history = history
+	page := v.VisiblePage()
+
+	if old == nil {
+		v.stack.SetTransitionType(gtk.StackTransitionTypeNone)
+	} else {
+		v.stack.SetTransitionType(slideTransition(forward))
+	}
+
+	if page != nil {
+		v.stack.SetVisibleChild(page.child)
+	}
+
+	v.updateBack()
+	v.removeHidden()
+
+	if old != nil {
+		old.onHidden.call()
+	}
+	if page != nil {
+		page.onShown.call()
+	}
+	v.onVisible.call()
+}
+
+func (v *NavigationView) updateBack() {
+	page := v.VisiblePage()
+	v.popAction.SetEnabled(len(v.history) > 1 && page.canPop)
+}
+
+func (v *NavigationView) addPage(page *NavigationPage) {
+	if page.view == v {
+		return
+	}
+	page.view = v
+	v.pages = append(v.pages, page)
+	v.stack.AddChild(page.child)
+}
+
+// removeHidden removes the pages that are neither in the navigation stack nor
+// added from the view once they're no longer sliding out.
+func (v *NavigationView) removeHidden() {
+	if v.stack.TransitionRunning() {
+		return
+	}
+
+	pages := v.pages[:0]
+	for _, page := range v.pages {
+		if v.isAdded(page) || v.isPushed(page) {
+			pages = append(pages, page)
+			continue
+		}
+		page.view = nil
+		v.stack.Remove(page.child)
+	}
+	v.pages = pages
+}
+
+func (v *NavigationView) isAdded(page *NavigationPage) bool {
+	for _, added := range v.added {
+		if added == page {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *NavigationView) isPushed(page *NavigationPage) bool {
+	for _, pushed := range v.history {
+		if pushed == page {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package adaptive_test
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleNavigationView() {
+	testapp.Run("navigation-view", func(app *gtk.Application) {
+		adaptive.Init()
+
+		view := adaptive.NewNavigationView()
+
+		var newPage func(depth int) *adaptive.NavigationPage
+		newPage = func(depth int) *adaptive.NavigationPage {
+			next := gtk.NewButtonWithLabel("Next")
+			next.SetHAlign(gtk.AlignCenter)
+			next.SetVAlign(gtk.AlignCenter)
+			next.ConnectClicked(func() { view.Push(newPage(depth + 1)) })
+
+			return adaptive.NewNavigationPage(next, fmt.Sprintf("Page %d", depth), "")
+		}
+
+		settings := adaptive.NewNavigationPage(gtk.NewLabel("Settings"), "Settings", "settings")
+		view.Add(settings)
+		view.Push(newPage(1))
+
+		back := gtk.NewButtonFromIconName("go-previous-symbolic")
+		back.SetActionName("navigation.pop")
+
+		settingsButton := gtk.NewButtonFromIconName("emblem-system-symbolic")
+		settingsButton.ConnectClicked(func() { view.PushByTag("settings") })
+
+		title := gtk.NewLabel("")
+		view.NotifyVisiblePage(func(page *adaptive.NavigationPage) {
+			title.SetText(page.Title())
+		})
+
+		h := gtk.NewHeaderBar()
+		h.SetTitleWidget(title)
+		h.PackStart(back)
+		h.PackEnd(settingsButton)
+
+		w := testapp.NewWindow(app, "Example Navigation", 450, 300)
+		w.InsertActionGroup("navigation", view.ActionGroup())
+		w.SetChild(view)
+		w.SetTitlebar(h)
+		w.Show()
+	})
+	// Output:
+}
@@ -0,0 +1,154 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// SizeClass describes how much horizontal space a window has.
+type SizeClass int
+
+const (
+	// SizeCompact is the size class of phones and very narrow windows.
+	SizeCompact SizeClass = iota
+	// SizeMedium is the size class of tablets and narrow desktop windows.
+	SizeMedium
+	// SizeExpanded is the size class of wide desktop windows.
+	SizeExpanded
+)
+
+// String returns the name of the size class.
+func (c SizeClass) String() string {
+	switch c {
+	case SizeCompact:
+		return "compact"
+	case SizeMedium:
+		return "medium"
+	case SizeExpanded:
+		return "expanded"
+	default:
+		return "unknown"
+	}
+}
+
+// CSSClass returns the CSS class that is added to the root widget while it is
+// in this size class, e.g. "adaptive-compact".
+func (c SizeClass) CSSClass() string {
+	return "adaptive-" + c.String()
+}
+
+// SizeClassThresholds are the window widths at which the size class changes.
+type SizeClassThresholds struct {
+	// Medium is the minimum width for SizeMedium.
+	Medium int
+	// Expanded is the minimum width for SizeExpanded.
+	Expanded int
+}
+
+// DefaultSizeClassThresholds are the default size class thresholds.
+var DefaultSizeClassThresholds = SizeClassThresholds{
+	Medium:   600,
+	Expanded: 840,
+}
+
+// SizeClassOf returns the size class for the given width.
+func (t SizeClassThresholds) SizeClassOf(width int) SizeClass {
+	switch {
+	case width >= t.Expanded:
+		return SizeExpanded
+	case width >= t.Medium:
+		return SizeMedium
+	default:
+		return SizeCompact
+	}
+}
+
+// WindowSizeClass keeps track of the size class of a window. It is a widget
+// that holds the window's content, so it should be set as the window's child.
+// The size class then follows the width that is allocated to the content,
+// which excludes the window's client-side decorations and shadows. It adds the
+// CSS class of the current size class to the window, so stylesheets can use
+// selectors like ".adaptive-compact".
+type WindowSizeClass struct {
+	*gtk.Overlay
+	size  *sizeWatcher
+	root  *gtk.Widget
+	thres SizeClassThresholds
+	class SizeClass
+
+	subs callbacks
+}
+
+// NewWindowSizeClass creates a new WindowSizeClass. Use SetChild to set the
+// window's content.
+func NewWindowSizeClass() *WindowSizeClass {
+	s := &WindowSizeClass{
+		thres: DefaultSizeClassThresholds,
+		class: SizeCompact,
+	}
+
+	s.Overlay = gtk.NewOverlay()
+	s.Overlay.AddCSSClass("adaptive-sizeclass")
+	s.size = watchSize(s.Overlay, func(int, int) { s.update() })
+
+	// Keep the CSS class on whichever window we're in.
+	s.Overlay.ConnectRealize(func() {
+		if root, ok := s.Overlay.Root().(gtk.Widgetter); ok {
+			s.root = gtk.BaseWidget(root)
+			s.root.AddCSSClass(s.class.CSSClass())
+		}
+	})
+	s.Overlay.ConnectUnrealize(func() {
+		if s.root != nil {
+			s.root.RemoveCSSClass(s.class.CSSClass())
+			s.root = nil
+		}
+	})
+
+	return s
+}
+
+// SizeClass returns the current size class.
+func (s *WindowSizeClass) SizeClass() SizeClass {
+	return s.class
+}
+
+// SetThresholds sets the widths at which the size class changes.
+func (s *WindowSizeClass) SetThresholds(thres SizeClassThresholds) {
+	s.thres = thres
+	s.update()
+}
+
+// Thresholds returns the widths at which the size class changes.
+func (s *WindowSizeClass) Thresholds() SizeClassThresholds {
+	return s.thres
+}
+
+// NotifySizeClass subscribes fn to be called when the size class changes. fn
+// is also called once with the current size class. The returned callback
+// unsubscribes fn.
+func (s *WindowSizeClass) NotifySizeClass(fn func(SizeClass)) func() {
+	remove := s.subs.add(func() { fn(s.class) })
+	fn(s.class)
+	return remove
+}
+
+func (s *WindowSizeClass) update() {
+	width, _ := s.size.Size()
+	if width == 0 {
+		// Not allocated yet.
+		return
+	}
+
+	class := s.thres.SizeClassOf(width)
+	if class == s.class {
+		return
+	}
+
+	if s.root != nil {
+		s.root.RemoveCSSClass(s.class.CSSClass())
+		s.root.AddCSSClass(class.CSSClass())
+	}
+	s.class = class
+
+	s.subs.call()
+}
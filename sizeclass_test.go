@@ -0,0 +1,56 @@
+package adaptive_test
+
+import (
+	"testing"
+
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleWindowSizeClass() {
+	testapp.Run("window-size-class", func(app *gtk.Application) {
+		adaptive.Init()
+
+		label := gtk.NewLabel("")
+
+		sizeClass := adaptive.NewWindowSizeClass()
+		sizeClass.SetChild(label)
+		sizeClass.NotifySizeClass(func(class adaptive.SizeClass) {
+			label.SetText("This window is " + class.String() + ".")
+		})
+
+		w := testapp.NewWindow(app, "Size Class", 500, 200)
+		w.SetChild(sizeClass)
+
+		w.Show()
+	})
+	// Output:
+}
+
+func TestSizeClassOf(t *testing.T) {
+	custom := adaptive.SizeClassThresholds{Medium: 100, Expanded: 200}
+
+	tests := []struct {
+		thres adaptive.SizeClassThresholds
+		width int
+		want  adaptive.SizeClass
+	}{
+		{adaptive.DefaultSizeClassThresholds, 0, adaptive.SizeCompact},
+		{adaptive.DefaultSizeClassThresholds, 599, adaptive.SizeCompact},
+		{adaptive.DefaultSizeClassThresholds, 600, adaptive.SizeMedium},
+		{adaptive.DefaultSizeClassThresholds, 839, adaptive.SizeMedium},
+		{adaptive.DefaultSizeClassThresholds, 840, adaptive.SizeExpanded},
+		{adaptive.DefaultSizeClassThresholds, 4000, adaptive.SizeExpanded},
+		{custom, 99, adaptive.SizeCompact},
+		{custom, 100, adaptive.SizeMedium},
+		{custom, 199, adaptive.SizeMedium},
+		{custom, 200, adaptive.SizeExpanded},
+	}
+
+	for _, test := range tests {
+		if got := test.thres.SizeClassOf(test.width); got != test.want {
+			t.Errorf("%+v.SizeClassOf(%d) = %v, want %v", test.thres, test.width, got, test.want)
+		}
+	}
+}
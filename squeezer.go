@@ -0,0 +1,220 @@
+package adaptive
+
+import (
+	"github.com/diamondburned/gotk4/pkg/glib/v2"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+// SqueezerPolicy determines which size of a Squeezer's children is compared
+// against the available width.
+type SqueezerPolicy int
+
+const (
+	// SqueezerNatural shows the first child whose natural width fits. It is
+	// the default policy.
+	SqueezerNatural SqueezerPolicy = iota
+	// SqueezerMinimum shows the first child whose minimum width fits.
+	SqueezerMinimum
+)
+
+// Squeezer is a component that acts similar to libadwaita's AdwSqueezer. It
+// holds several alternative children, such as a full view switcher, a compact
+// one and a plain title, and shows the first one that fits into its allocated
+// width, crossfading between them. If none of them fits, then the last one is
+// shown.
+type Squeezer struct {
+	*gtk.Widget
+	overlay  *gtk.Overlay
+	scroll   *gtk.ScrolledWindow
+	stack    *gtk.Stack
+	spacer   *gtk.Box
+	size     *sizeWatcher
+	children []squeezerChild
+	policy   SqueezerPolicy
+}
+
+type squeezerChild struct {
+	widget gtk.Widgetter
+	// visible is the handler of the child's notify::visible signal.
+	visible glib.SignalHandle
+}
+
+// NewSqueezer creates a new empty squeezer.
+func NewSqueezer() *Squeezer {
+	s := &Squeezer{}
+
+	s.stack = gtk.NewStack()
+	s.stack.SetTransitionType(gtk.StackTransitionTypeCrossfade)
+	s.stack.SetHhomogeneous(false)
+	s.stack.SetVhomogeneous(false)
+	s.stack.SetInterpolateSize(false)
+
+	// The stack is kept in a scrolled window without scrollbars, so that the
+	// squeezer can be made narrower than the visible child, which lets the
+	// next child take its place.
+	s.scroll = gtk.NewScrolledWindow()
+	s.scroll.SetPolicy(gtk.PolicyExternal, gtk.PolicyNever)
+	s.scroll.SetPropagateNaturalWidth(true)
+	s.scroll.SetPropagateNaturalHeight(true)
+	s.scroll.SetChild(s.stack)
+
+	s.overlay = gtk.NewOverlay()
+	s.overlay.AddCSSClass("adaptive-squeezer")
+	s.overlay.SetChild(s.scroll)
+
+	// The squeezer's natural width is that of its widest child, but the stack
+	// only reports the visible child's. The spacer is as wide as the widest
+	// child, and its own scrolled window keeps it from raising the squeezer's
+	// minimum width.
+	s.spacer = gtk.NewBox(gtk.OrientationHorizontal, 0)
+
+	spacerScroll := gtk.NewScrolledWindow()
+	spacerScroll.SetPolicy(gtk.PolicyExternal, gtk.PolicyNever)
+	spacerScroll.SetPropagateNaturalWidth(true)
+	spacerScroll.SetCanTarget(false)
+	spacerScroll.SetCanFocus(false)
+	spacerScroll.SetChild(s.spacer)
+
+	s.overlay.AddOverlay(spacerScroll)
+	s.overlay.SetMeasureOverlay(spacerScroll, true)
+
+	s.Widget = gtk.BaseWidget(s.overlay)
+	// The children's widths may change without the squeezer's own size
+	// changing, so the fitting child is picked again after every allocation.
+	s.size = watchAllocation(s.overlay, func(int, int) { s.update() })
+
+	return s
+}
+
+// Append appends the given child to the squeezer. Children that are added
+// earlier are preferred over later ones, so the widest alternative should be
+// added first.
+func (s *Squeezer) Append(child gtk.Widgetter) {
+	s.children = append(s.children, squeezerChild{
+		widget:  child,
+		visible: gtk.BaseWidget(child).NotifyProperty("visible", s.update),
+	})
+	s.stack.AddChild(child)
+	s.update()
+}
+
+// Remove removes the given child from the squeezer.
+func (s *Squeezer) Remove(child gtk.Widgetter) {
+	for i, c := range s.children {
+		if glib.ObjectEq(c.widget, child) {
+			gtk.BaseWidget(child).HandlerDisconnect(c.visible)
+			s.children = append(s.children[:i], s.children[i+1:]...)
+			s.stack.Remove(child)
+			s.update()
+			return
+		}
+	}
+}
+
+// VisibleChild returns the child that is currently shown, or nil if the
+// squeezer is empty.
+func (s *Squeezer) VisibleChild() gtk.Widgetter {
+	if len(s.children) == 0 {
+		return nil
+	}
+	return s.stack.VisibleChild()
+}
+
+// SetPolicy sets whether the natural or the minimum width of the children is
+// compared against the available width.
+func (s *Squeezer) SetPolicy(policy SqueezerPolicy) {
+	s.policy = policy
+	s.update()
+}
+
+// Policy returns the squeezer's policy.
+func (s *Squeezer) Policy() SqueezerPolicy {
+	return s.policy
+}
+
+// SetHomogeneous sets whether or not the squeezer requests the same height for
+// all its children, so that its height doesn't change when it switches between
+// them. It is false by default. The squeezer's natural width is always that of
+// its widest child.
+func (s *Squeezer) SetHomogeneous(homogeneous bool) {
+	s.stack.SetVhomogeneous(homogeneous)
+}
+
+// IsHomogeneous returns true if the squeezer is homogeneous.
+func (s *Squeezer) IsHomogeneous() bool {
+	return s.stack.Vhomogeneous()
+}
+
+// SetTransitionDuration sets the duration in milliseconds of the crossfade
+// between children.
+func (s *Squeezer) SetTransitionDuration(duration uint) {
+	s.stack.SetTransitionDuration(duration)
+}
+
+// SetCrossfade sets whether or not the squeezer crossfades between children.
+// It is true by default.
+func (s *Squeezer) SetCrossfade(crossfade bool) {
+	if crossfade {
+		s.stack.SetTransitionType(gtk.StackTransitionTypeCrossfade)
+	} else {
+		s.stack.SetTransitionType(gtk.StackTransitionTypeNone)
+	}
+}
+
+func (s *Squeezer) update() {
+	var fit, smallest gtk.Widgetter
+	var smallestWidth, smallestMin, widest int
+
+	width, _ := s.size.Size()
+
+	for _, c := range s.children {
+		child := c.widget
+		if !gtk.BaseWidget(child).Visible() {
+			continue
+		}
+
+		// w is the width of the child according to the policy.
+		min, nat, _, _ := gtk.BaseWidget(child).Measure(gtk.OrientationHorizontal, -1)
+		w := nat
+		if s.policy == SqueezerMinimum {
+			w = min
+		}
+
+		if smallest == nil || w < smallestWidth {
+			smallest = child
+			smallestWidth = w
+			smallestMin = min
+		}
+
+		if nat > widest {
+			widest = nat
+		}
+
+		if fit == nil && w <= width {
+			fit = child
+		}
+	}
+
+	s.spacer.SetSizeRequest(widest, -1)
+
+	if smallest == nil {
+		return
+	}
+
+	// Never let the squeezer get smaller than its smallest child.
+	s.scroll.SetMinContentWidth(smallestMin)
+
+	if fit == nil {
+		// Nothing fits, so show the last visible child.
+		for i := len(s.children) - 1; i >= 0; i-- {
+			if gtk.BaseWidget(s.children[i].widget).Visible() {
+				fit = s.children[i].widget
+				break
+			}
+		}
+	}
+
+	if !glib.ObjectEq(s.stack.VisibleChild(), fit) {
+		s.stack.SetVisibleChild(fit)
+	}
+}
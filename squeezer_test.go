@@ -0,0 +1,35 @@
+package adaptive_test
+
+import (
+	"github.com/diamondburned/adaptive"
+	"github.com/diamondburned/adaptive/internal/testapp"
+	"github.com/diamondburned/gotk4/pkg/gtk/v4"
+)
+
+func ExampleSqueezer() {
+	testapp.Run("squeezer", func(app *gtk.Application) {
+		adaptive.Init()
+
+		stack := newStack()
+
+		switcher := gtk.NewStackSwitcher()
+		switcher.SetStack(stack)
+
+		title := gtk.NewLabel("Example Squeezer")
+		title.AddCSSClass("title")
+
+		squeezer := adaptive.NewSqueezer()
+		squeezer.SetHExpand(true)
+		squeezer.Append(switcher)
+		squeezer.Append(title)
+
+		h := gtk.NewHeaderBar()
+		h.SetTitleWidget(squeezer)
+
+		w := testapp.NewWindow(app, "Example Squeezer", 600, 300)
+		w.SetChild(stack)
+		w.SetTitlebar(h)
+		w.Show()
+	})
+	// Output:
+}